@@ -0,0 +1,66 @@
+package dbx
+
+import "sync"
+
+// afterCommit holds the callbacks registered via RegisterAfterCommit within a transaction or a savepoint.
+type afterCommit struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// RegisterAfterCommit registers a given function to be called once the transaction of a given context is committed,
+// e.g. to publish an event only if the changes are persisted.
+// The function is called after the commit of the outermost transaction created by Transaction,
+// so code performed within a reused transaction may register side effects without knowing who owns the transaction.
+// Functions registered within a savepoint are discarded if the transaction is rolled back to it.
+// If the context is not bound to a transaction created by Transaction, the function is called immediately.
+func RegisterAfterCommit(ctx Context, fn func()) {
+	callbacks, ok := GetValue[*afterCommit](ctx)
+
+	if !ok {
+		fn()
+
+		return
+	}
+
+	callbacks.add(fn)
+}
+
+// PendingAfterCommit returns the number of functions registered via RegisterAfterCommit
+// that wait for the transaction of a given context to be committed, e.g. to assert the registration in tests.
+// Within a savepoint, only the functions registered within it are counted.
+// If the context is not bound to a transaction created by Transaction, it returns 0.
+func PendingAfterCommit(ctx Context) int {
+	callbacks, ok := GetValue[*afterCommit](ctx)
+
+	if !ok {
+		return 0
+	}
+
+	return callbacks.len()
+}
+
+func (c *afterCommit) add(fns ...func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fns = append(c.fns, fns...)
+}
+
+// drain returns the registered callbacks and forgets them.
+func (c *afterCommit) drain() []func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fns := c.fns
+	c.fns = nil
+
+	return fns
+}
+
+func (c *afterCommit) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.fns)
+}
@@ -0,0 +1,147 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestRegisterAfterCommit(test *testing.T) {
+	test.Run("should call callbacks after outermost commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		var calls []string
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			dbx.RegisterAfterCommit(c, func() {
+				calls = append(calls, "outer")
+			})
+
+			err := dbx.Transaction(c, db, func(c dbx.Context) error {
+				dbx.RegisterAfterCommit(c, func() {
+					calls = append(calls, "inner")
+				})
+
+				return nil
+			})
+
+			assert.Empty(t, calls)
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"outer", "inner"}, calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not call callbacks after rollback", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			dbx.RegisterAfterCommit(c, func() {
+				t.Fatal("callback is called")
+			})
+
+			return testErr
+		})
+
+		assert.Equal(t, testErr, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should discard callbacks of savepoint rolled back to", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("ROLLBACK TO SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		var calls []string
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			dbx.Transaction(c, db, func(c dbx.Context) error {
+				dbx.RegisterAfterCommit(c, func() {
+					calls = append(calls, "discarded")
+				})
+
+				return errors.New("test error")
+			}, dbx.WithSavepoint())
+
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				dbx.RegisterAfterCommit(c, func() {
+					calls = append(calls, "released")
+				})
+
+				return nil
+			}, dbx.WithSavepoint())
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"released"}, calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should call callback immediately without transaction", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		called := false
+
+		dbx.RegisterAfterCommit(db.Context(context.Background()), func() {
+			called = true
+		})
+
+		assert.True(t, called)
+	})
+}
+
+func TestPendingAfterCommit(test *testing.T) {
+	test.Run("should count callbacks before commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		var txCtx dbx.Context
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			txCtx = c
+
+			dbx.RegisterAfterCommit(c, func() {})
+			dbx.RegisterAfterCommit(c, func() {})
+
+			assert.Equal(t, 2, dbx.PendingAfterCommit(c))
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, dbx.PendingAfterCommit(txCtx))
+		assert.Equal(t, 0, dbx.PendingAfterCommit(db.Context(context.Background())))
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,101 @@
+package dbx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var placeholderList = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+
+// WithQueryAllowlist returns an executor that only executes queries whose fingerprints are in a given allowlist.
+// Other queries fail with ErrQueryNotAllowed without reaching the database.
+// Transactions started from a context with the returned executor enforce the allowlist as well.
+// Statements generated by dbx itself, e.g. savepoint statements of WithSavepoint, are not checked.
+// Use Fingerprint to generate the allowlist from the known queries.
+func WithQueryAllowlist(exec Executor, fingerprints map[string]bool) Executor {
+	return withHook(exec, func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error {
+		if isInternal(ctx) {
+			return next(ctx)
+		}
+
+		fingerprint := Fingerprint(stmt.query)
+
+		if !fingerprints[fingerprint] {
+			return fmt.Errorf("%w: %s", ErrQueryNotAllowed, truncateQuery(fingerprint, stmt.maxLength))
+		}
+
+		return next(ctx)
+	})
+}
+
+// Fingerprint normalizes a given query into a shape that does not depend on literals and formatting.
+// String, numeric and dollar-quoted literals as well as placeholders are replaced with "?",
+// lists of them are collapsed into a single "?", comments are removed, whitespaces are collapsed
+// and everything except quoted identifiers is lowercased.
+func Fingerprint(query string) string {
+	var sb strings.Builder
+	space := false
+
+	emit := func(s string) {
+		// keep whitespaces only where they separate words
+		if space && sb.Len() > 0 && isWord(sb.String()[sb.Len()-1]) && isWord(s[0]) {
+			sb.WriteByte(' ')
+		}
+
+		space = false
+		sb.WriteString(s)
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == '\'':
+			i = skipQuoted(query, i)
+			emit("?")
+		case c == '"' || c == '`':
+			end := skipQuoted(query, i)
+			emit(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "--"):
+			i = skipLineComment(query, i)
+			space = true
+		case strings.HasPrefix(query[i:], "/*"):
+			i = skipBlockComment(query, i)
+			space = true
+		case c == '$' && (i == 0 || !isIdentifier(query[i-1])):
+			end := skipDollarQuoted(query, i)
+
+			// positional parameter, e.g. $1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+
+			emit("?")
+			i = end
+		case isDigit(c) && (i == 0 || !isIdentifier(query[i-1])):
+			for i < len(query) && (isDigit(query[i]) || query[i] == '.') {
+				i++
+			}
+
+			emit("?")
+		case isSpace(c):
+			space = true
+			i++
+		case c >= 'A' && c <= 'Z':
+			emit(string(c + 'a' - 'A'))
+			i++
+		default:
+			emit(query[i : i+1])
+			i++
+		}
+	}
+
+	return placeholderList.ReplaceAllString(sb.String(), "?")
+}
+
+func isWord(c byte) bool {
+	return isIdentifier(c) || c == '?' || c == '"' || c == '`'
+}
@@ -0,0 +1,124 @@
+package dbx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestFingerprint(test *testing.T) {
+	test.Run("should ignore literals and formatting", func(t *testing.T) {
+		expected := "select id,name from users where id=? and name=? and status in(?)"
+
+		queries := []string{
+			"SELECT id, name FROM users WHERE id = 1 AND name = 'John' AND status IN (1, 2, 3)",
+			"select id,name\n  from users -- all users\n where id=42 and name='O''Brien' and status in(7)",
+			"SELECT id, name FROM users WHERE id = $1 AND name = $2 AND status IN ($3, $4)",
+			"SELECT /* hint */ id, name FROM users WHERE id = ? AND name = ? AND status IN (?, ?)",
+		}
+
+		for _, query := range queries {
+			assert.Equal(t, expected, dbx.Fingerprint(query), query)
+		}
+	})
+
+	test.Run("should keep identifiers", func(t *testing.T) {
+		assert.NotEqual(t, dbx.Fingerprint("SELECT * FROM users"), dbx.Fingerprint("SELECT * FROM admins"))
+		assert.NotEqual(t, dbx.Fingerprint("SELECT col1 FROM t"), dbx.Fingerprint("SELECT col2 FROM t"))
+		assert.Equal(t, `select "Name" from t`, dbx.Fingerprint(`SELECT "Name" FROM t`))
+	})
+}
+
+func TestWithQueryAllowlist(test *testing.T) {
+	allowlist := map[string]bool{
+		dbx.Fingerprint("SELECT name FROM users WHERE id = ?"):    true,
+		dbx.Fingerprint("UPDATE users SET name = ? WHERE id = ?"): true,
+	}
+
+	test.Run("should execute allowed queries", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name FROM users").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+		exec := dbx.WithQueryAllowlist(db, allowlist)
+
+		var name string
+		err := exec.QueryRow("SELECT name FROM users WHERE id = 42").Scan(&name)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "John", name)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should reject unknown queries", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		exec := dbx.WithQueryAllowlist(db, allowlist)
+
+		_, err := exec.Exec("DELETE FROM users")
+		assert.ErrorIs(t, err, dbx.ErrQueryNotAllowed)
+
+		var name string
+		err = exec.QueryRow("SELECT password FROM users WHERE id = 1").Scan(&name)
+		assert.ErrorIs(t, err, dbx.ErrQueryNotAllowed)
+
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should enforce allowlist in transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectRollback()
+
+		ctx := dbx.NewContext(context.Background(), dbx.WithQueryAllowlist(db, allowlist))
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			if _, e := c.Executor().Exec("UPDATE users SET name = 'John' WHERE id = 1"); e != nil {
+				return e
+			}
+
+			_, e := c.Executor().Exec("DROP TABLE users")
+
+			return e
+		})
+
+		assert.ErrorIs(t, err, dbx.ErrQueryNotAllowed)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not check savepoint statements", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		ctx := dbx.NewContext(context.Background(), dbx.WithQueryAllowlist(db, allowlist))
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				_, e := c.Executor().Exec("UPDATE users SET name = 'John' WHERE id = 1")
+
+				return e
+			}, dbx.WithSavepoint())
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
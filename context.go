@@ -2,15 +2,25 @@ package dbx
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 )
 
 type (
 	ctxKey struct{}
 
+	valueKey[T any] struct{}
+
 	defaultContext struct {
 		parent   context.Context
 		executor Executor
+		cancel   context.CancelCauseFunc
+		finished *atomic.Bool
+	}
+
+	// valuesContext exposes the values of a given context, but never gets done.
+	valuesContext struct {
+		context.Context
 	}
 )
 
@@ -36,6 +46,64 @@ func NewContext(parent context.Context, exec Executor) Context {
 	}
 }
 
+// NewTransactorContext returns a new context with a given Transactor.
+// Transaction and TransactionWithResult reuse the transactor instead of beginning a new transaction.
+// The caller remains responsible for committing or rolling back the transactor.
+func NewTransactorContext(parent context.Context, tx Transactor) Context {
+	return NewContext(parent, tx)
+}
+
+// newTxContext returns a new context with a given transaction that can be cancelled via Context.Cancel
+// and reports it inactive via Context.IsActive once a given flag is set.
+func newTxContext(parent context.Context, tx Transactor, cancel context.CancelCauseFunc, finished *atomic.Bool) Context {
+	return &defaultContext{
+		parent:   parent,
+		executor: tx,
+		cancel:   cancel,
+		finished: finished,
+	}
+}
+
+// withExecutor returns a copy of a given context with a different executor.
+func withExecutor(ctx Context, exec Executor) Context {
+	if c, ok := ctx.(*defaultContext); ok {
+		cp := *c
+		cp.executor = exec
+
+		return &cp
+	}
+
+	return NewContext(ctx, exec)
+}
+
+// Detach returns a copy of a given context that keeps its executor and values, but is never cancelled and has no deadline,
+// so that the work started in a goroutine may outlive the original context, e.g. best-effort logging after a request is done.
+// Note that the executor may still be tied to a transaction that is finished or cancelled along with the original context,
+// so detached work should use a non-transactional executor.
+func Detach(dbCtx Context) Context {
+	return NewContext(valuesContext{dbCtx}, dbCtx.Executor())
+}
+
+// SetValue returns a copy of a given context that carries a given value keyed by its type.
+// The value is preserved by transactions created from the returned context.
+func SetValue[T any](dbCtx Context, v T) Context {
+	if c, ok := dbCtx.(*defaultContext); ok {
+		cp := *c
+		cp.parent = context.WithValue(c.parent, valueKey[T]{}, v)
+
+		return &cp
+	}
+
+	return NewContext(context.WithValue(dbCtx, valueKey[T]{}, v), dbCtx.Executor())
+}
+
+// GetValue returns a value of a given type set via SetValue.
+func GetValue[T any](ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(valueKey[T]{}).(T)
+
+	return v, ok
+}
+
 // NewContextFrom returns a DB context from a given context or creates a new one if an existing one not found in a given context.
 func NewContextFrom(ctx context.Context, creator ContextCreator) Context {
 	found := FromContext(ctx)
@@ -60,6 +128,18 @@ func FromContext(ctx context.Context) Context {
 	return nil
 }
 
+// Join returns the executor of a transaction found in a given context or the database otherwise.
+// It lets code that takes a Database participate in an ambient transaction without being restructured to take a Context.
+func Join(ctx context.Context, db Database) Executor {
+	if found := FromContext(ctx); found != nil {
+		if tx, ok := found.Executor().(Transactor); ok {
+			return tx
+		}
+	}
+
+	return db
+}
+
 // WithContext returns a new context with a given DB context.
 func WithContext(ctx context.Context, dbCtx Context) context.Context {
 	return context.WithValue(ctx, ctxKey{}, dbCtx)
@@ -84,3 +164,33 @@ func (c *defaultContext) Value(key interface{}) interface{} {
 func (c *defaultContext) Executor() Executor {
 	return c.executor
 }
+
+func (c *defaultContext) Cancel(cause error) {
+	if c.cancel != nil {
+		c.cancel(cause)
+	}
+}
+
+func (c *defaultContext) IsActive() bool {
+	return c.finished == nil || !c.finished.Load()
+}
+
+func (c *defaultContext) WithDeadline(d time.Time) (Context, context.CancelFunc) {
+	parent, cancel := context.WithDeadline(c.parent, d)
+	child := *c
+	child.parent = parent
+
+	return &child, cancel
+}
+
+func (valuesContext) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (valuesContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (valuesContext) Err() error {
+	return nil
+}
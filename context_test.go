@@ -0,0 +1,256 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestJoin(test *testing.T) {
+	test.Run("should return transaction executor when transaction is ambient", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			assert.Equal(t, c.Executor(), dbx.Join(c, db))
+			assert.Equal(t, c.Executor(), dbx.Join(dbx.WithContext(context.Background(), c), db))
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return database executor otherwise", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		assert.Equal(t, db, dbx.Join(context.Background(), db))
+		assert.Equal(t, db, dbx.Join(db.Context(context.Background()), db))
+	})
+}
+
+func TestContextWithDeadline(test *testing.T) {
+	test.Run("should share executor and shorten deadline", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			deadline := time.Now().Add(time.Second)
+			child, cancel := c.WithDeadline(deadline)
+			defer cancel()
+
+			parentDeadline, _ := c.Deadline()
+			childDeadline, ok := child.Deadline()
+
+			assert.True(t, ok)
+			assert.Equal(t, deadline, childDeadline)
+			assert.True(t, childDeadline.Before(parentDeadline))
+			assert.Equal(t, c.Executor(), child.Executor())
+			assert.Equal(t, child, dbx.FromContext(child))
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+type user struct {
+	Name string
+}
+
+func TestValues(test *testing.T) {
+	test.Run("should preserve values in nested transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectCommit()
+
+		ctx := dbx.SetValue(db.Context(context.Background()), user{Name: "John"})
+		ctx = dbx.SetValue(ctx, "en-US")
+
+		err := dbx.Transaction(ctx, db, func(c1 dbx.Context) error {
+			return dbx.Transaction(c1, db, func(c2 dbx.Context) error {
+				return dbx.Transaction(c2, db, func(c3 dbx.Context) error {
+					u, ok := dbx.GetValue[user](c3)
+					assert.True(t, ok)
+					assert.Equal(t, "John", u.Name)
+
+					locale, ok := dbx.GetValue[string](c3)
+					assert.True(t, ok)
+					assert.Equal(t, "en-US", locale)
+
+					return nil
+				})
+			}, dbx.WithNewTransaction())
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should keep executor", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		ctx := db.Context(context.Background())
+
+		assert.Equal(t, ctx.Executor(), dbx.SetValue(ctx, 1).Executor())
+	})
+
+	test.Run("should report missing values", func(t *testing.T) {
+		_, ok := dbx.GetValue[user](context.Background())
+
+		assert.False(t, ok)
+	})
+}
+
+func TestDetach(test *testing.T) {
+	test.Run("should not be cancelled along with original context", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		dbCtx := dbx.SetValue(dbx.NewContext(ctx, db), "request-id")
+		detached := dbx.Detach(dbCtx)
+		cancel()
+
+		<-dbCtx.Done()
+
+		select {
+		case <-detached.Done():
+			t.Fatal("detached context is done")
+		default:
+		}
+
+		_, ok := detached.Deadline()
+		value, found := dbx.GetValue[string](detached)
+
+		assert.False(t, ok)
+		assert.NoError(t, detached.Err())
+		assert.Equal(t, dbCtx.Executor(), detached.Executor())
+		assert.True(t, found)
+		assert.Equal(t, "request-id", value)
+	})
+}
+
+func TestContextIsActive(test *testing.T) {
+	test.Run("should become inactive after commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		var txCtx dbx.Context
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			txCtx = c
+
+			assert.True(t, c.IsActive())
+
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				assert.True(t, c.IsActive())
+
+				return nil
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, txCtx.IsActive())
+		assert.True(t, db.Context(context.Background()).IsActive())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should become inactive after rollback", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		var txCtx dbx.Context
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			txCtx = c
+
+			return testErr
+		})
+
+		assert.Equal(t, testErr, err)
+		assert.False(t, txCtx.IsActive())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should be inactive within callbacks", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		var txCtx dbx.Context
+		active := make([]bool, 0, 3)
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			txCtx = c
+
+			dbx.RegisterAfterCommit(c, func() {
+				active = append(active, txCtx.IsActive())
+			})
+
+			return nil
+		}, dbx.WithOnCommit(func() {
+			active = append(active, txCtx.IsActive())
+		}))
+
+		assert.NoError(t, err)
+
+		err = dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			txCtx = c
+
+			return testErr
+		}, dbx.WithOnRollback(func(err error) {
+			active = append(active, txCtx.IsActive())
+		}))
+
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, []bool{false, false, false}, active)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
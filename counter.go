@@ -0,0 +1,89 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+// countingTransactor counts statements executed within a transaction and the rows they affect.
+// If the limit is set, statements beyond it fail with ErrStatementLimitExceeded without reaching the database.
+type countingTransactor struct {
+	Transactor
+	limit        int64
+	statements   atomic.Int64
+	rowsAffected atomic.Int64
+}
+
+func (t *countingTransactor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if err := t.count(); err != nil {
+		return nil, err
+	}
+
+	return t.countResult(t.Transactor.Exec(query, args...))
+}
+
+func (t *countingTransactor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	if err := t.count(); err != nil {
+		return nil, err
+	}
+
+	return t.Transactor.Query(query, args...)
+}
+
+func (t *countingTransactor) QueryRow(query string, args ...interface{}) *sql.Row {
+	if err := t.count(); err != nil {
+		return newErrorRow(err)
+	}
+
+	return t.Transactor.QueryRow(query, args...)
+}
+
+func (t *countingTransactor) ExecContext(dbContext context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := t.count(); err != nil {
+		return nil, err
+	}
+
+	return t.countResult(t.Transactor.ExecContext(dbContext, query, args...))
+}
+
+func (t *countingTransactor) QueryContext(dbContext context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := t.count(); err != nil {
+		return nil, err
+	}
+
+	return t.Transactor.QueryContext(dbContext, query, args...)
+}
+
+func (t *countingTransactor) QueryRowContext(dbContext context.Context, query string, args ...interface{}) *sql.Row {
+	if err := t.count(); err != nil {
+		return newErrorRow(err)
+	}
+
+	return t.Transactor.QueryRowContext(dbContext, query, args...)
+}
+
+// count counts a statement unless it exceeds the limit.
+func (t *countingTransactor) count() error {
+	n := t.statements.Add(1)
+
+	if t.limit > 0 && n > t.limit {
+		t.statements.Add(-1)
+
+		return ErrStatementLimitExceeded
+	}
+
+	return nil
+}
+
+func (t *countingTransactor) countResult(res sql.Result, err error) (sql.Result, error) {
+	if err != nil {
+		return res, err
+	}
+
+	if affected, e := res.RowsAffected(); e == nil {
+		t.rowsAffected.Add(affected)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package dbx
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// QueryCSV executes a given query and writes its result to a given writer as CSV.
+func QueryCSV(ctx Context, w io.Writer, query string, args ...interface{}) error {
+	rows, err := ctx.Executor().QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	return RowsToCSV(rows, w)
+}
+
+// RowsToCSV writes a header with column names followed by each row to a given writer as CSV and closes the rows.
+// NULL values are written as empty fields, byte slices as strings and times in RFC 3339 format.
+func RowsToCSV(rows *sql.Rows, w io.Writer) error {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+
+	if err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	record := make([]string, len(columns))
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		for i, value := range values {
+			record[i] = formatCSV(value)
+		}
+
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+func formatCSV(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
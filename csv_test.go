@@ -0,0 +1,37 @@
+package dbx_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestQueryCSV(test *testing.T) {
+	test.Run("should write header and rows", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		dmock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "bio", "created_at"}).
+				AddRow(1, "John", []byte("likes \"quotes\", commas"), created).
+				AddRow(2, "Jane", nil, created),
+		).RowsWillBeClosed()
+
+		var sb strings.Builder
+
+		err := dbx.QueryCSV(db.Context(context.Background()), &sb, "SELECT id, name, bio, created_at FROM users")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id,name,bio,created_at\n"+
+			"1,John,\"likes \"\"quotes\"\", commas\",2023-01-02T03:04:05Z\n"+
+			"2,Jane,,2023-01-02T03:04:05Z\n", sb.String())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -3,14 +3,88 @@ package dbx
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
 )
 
-type defaultDatabase struct {
-	db *sql.DB
+type (
+	databaseOptions struct {
+		DriverName                string
+		MaxConcurrentTransactions int
+		TransactionLimitMode      TransactionLimitMode
+	}
+
+	// DatabaseOption configures a database created by New.
+	DatabaseOption func(opts *databaseOptions)
+
+	defaultDatabase struct {
+		db      *sql.DB
+		opts    *databaseOptions
+		limiter *semaphore
+	}
+)
+
+// WithDriverName sets the name of the driver reported by Database.DriverName.
+func WithDriverName(name string) DatabaseOption {
+	return func(opts *databaseOptions) {
+		opts.DriverName = name
+	}
+}
+
+// WithMaxConcurrentTransactions limits the number of transactions created by Transaction and TransactionWithResult
+// that run concurrently on the database, so that a burst of transactions does not exhaust the connection pool.
+// Once the limit is reached, a new transaction waits or fails with ErrTooManyTransactions according to a given mode.
+// With NewRouter, every resolved database enforces its own limit, e.g. per tenant.
+// Transactions started via Begin or BeginTx directly are not limited.
+func WithMaxConcurrentTransactions(n int, mode TransactionLimitMode) DatabaseOption {
+	return func(opts *databaseOptions) {
+		opts.MaxConcurrentTransactions = n
+		opts.TransactionLimitMode = mode
+	}
+}
+
+func New(db *sql.DB, setters ...DatabaseOption) Database {
+	opts := &databaseOptions{}
+
+	for _, setter := range setters {
+		setter(opts)
+	}
+
+	var limiter *semaphore
+
+	if opts.MaxConcurrentTransactions > 0 {
+		limiter = newSemaphore(opts.MaxConcurrentTransactions, opts.TransactionLimitMode)
+	}
+
+	return &defaultDatabase{db, opts, limiter}
+}
+
+// DriverName returns the driver name set via WithDriverName.
+// Otherwise, it returns the package name of the driver type, e.g. "pq" or "mysql",
+// since database/sql does not expose the name the driver is registered with.
+func (d *defaultDatabase) DriverName() string {
+	if d.opts.DriverName != "" {
+		return d.opts.DriverName
+	}
+
+	// e.g. *pq.Driver
+	name := strings.TrimLeft(reflect.TypeOf(d.db.Driver()).String(), "*")
+
+	if pkg, _, found := strings.Cut(name, "."); found {
+		return pkg
+	}
+
+	return name
 }
 
-func New(db *sql.DB) Database {
-	return &defaultDatabase{db}
+func (d *defaultDatabase) acquireTransaction(ctx context.Context) (func(), error) {
+	if d.limiter == nil {
+		return func() {}, nil
+	}
+
+	return d.limiter.acquire(ctx)
 }
 
 func (d *defaultDatabase) Close() error {
@@ -21,14 +95,20 @@ func (d *defaultDatabase) Context(ctx context.Context) Context {
 	return NewContext(ctx, d)
 }
 
+// Begin starts a transaction via BeginTx with a background context and default options.
+// BeginTx is preferred, since it allows the transaction to be cancelled.
 func (d *defaultDatabase) Begin() (*sql.Tx, error) {
-	return d.db.Begin()
+	return d.BeginTx(context.Background(), nil)
 }
 
 func (d *defaultDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
 	return d.db.BeginTx(ctx, opts)
 }
 
+func (d *defaultDatabase) Conn(ctx context.Context) (*sql.Conn, error) {
+	return d.db.Conn(ctx)
+}
+
 func (d *defaultDatabase) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return d.db.Exec(query, args...)
 }
@@ -52,3 +132,57 @@ func (d *defaultDatabase) QueryContext(dbContext context.Context, query string,
 func (d *defaultDatabase) QueryRowContext(dbContext context.Context, query string, args ...interface{}) *sql.Row {
 	return d.db.QueryRowContext(dbContext, query, args...)
 }
+
+func (d *defaultDatabase) Ping() error {
+	return d.db.Ping()
+}
+
+func (d *defaultDatabase) PingContext(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+func (d *defaultDatabase) Stats() sql.DBStats {
+	return d.db.Stats()
+}
+
+func (d *defaultDatabase) SetMaxOpenConns(n int) {
+	d.db.SetMaxOpenConns(n)
+}
+
+func (d *defaultDatabase) SetMaxIdleConns(n int) {
+	d.db.SetMaxIdleConns(n)
+}
+
+func (d *defaultDatabase) SetConnMaxLifetime(duration time.Duration) {
+	d.db.SetConnMaxLifetime(duration)
+}
+
+func (d *defaultDatabase) WaitReady(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInterval, interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+
+	for {
+		err := d.db.PingContext(ctx)
+
+		if err == nil {
+			return nil
+		}
+
+		// prefer the error reported by the database over the context expiration
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package dbx_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestDatabase(test *testing.T) {
+	test.Run("should begin equivalent transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		begins := map[string]func() (*sql.Tx, error){
+			"Begin": db.Begin,
+			"BeginTx": func() (*sql.Tx, error) {
+				return db.BeginTx(context.Background(), nil)
+			},
+		}
+
+		for name, begin := range begins {
+			dmock.ExpectBegin()
+			dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+			dmock.ExpectCommit()
+
+			tx, err := begin()
+			assert.NoError(t, err, name)
+
+			_, err = tx.Exec("SELECT 1")
+			assert.NoError(t, err, name)
+			assert.NoError(t, tx.Commit(), name)
+			assert.NoError(t, dmock.ExpectationsWereMet(), name)
+		}
+	})
+
+	test.Run("should ping database", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		defer dbMock.Close()
+
+		testErr := errors.New("connection refused")
+		db := dbx.New(dbMock)
+		dmock.ExpectPing()
+		dmock.ExpectPing().WillReturnError(testErr)
+
+		assert.NoError(t, db.Ping())
+		assert.Equal(t, testErr, db.PingContext(context.Background()))
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should configure pool and return its stats", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		db.SetMaxOpenConns(3)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(time.Minute)
+
+		conn, err := db.Conn(context.Background())
+		assert.NoError(t, err)
+
+		defer conn.Close()
+
+		stats := db.Stats()
+
+		assert.Equal(t, 3, stats.MaxOpenConnections)
+		assert.Equal(t, 1, stats.InUse)
+	})
+
+	test.Run("should wait until database is ready", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		defer dbMock.Close()
+
+		testErr := errors.New("connection refused")
+		db := dbx.New(dbMock)
+		dmock.ExpectPing().WillReturnError(testErr)
+		dmock.ExpectPing().WillReturnError(testErr)
+		dmock.ExpectPing()
+
+		err := db.WaitReady(context.Background(), time.Millisecond)
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return last ping error on timeout", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		defer dbMock.Close()
+
+		testErr := errors.New("connection refused")
+		db := dbx.New(dbMock)
+
+		for i := 0; i < 100; i++ {
+			dmock.ExpectPing().WillReturnError(testErr)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := db.WaitReady(ctx, 10*time.Millisecond)
+
+		assert.Equal(t, testErr, err)
+	})
+
+	test.Run("should reject non-positive interval", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		err := db.WaitReady(context.Background(), 0)
+
+		assert.ErrorIs(t, err, dbx.ErrInvalidInterval)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return explicit driver name", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock, dbx.WithDriverName("postgres"))
+
+		assert.Equal(t, "postgres", db.DriverName())
+	})
+
+	test.Run("should detect driver name", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		assert.Equal(t, "sqlmock", db.DriverName())
+	})
+}
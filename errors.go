@@ -0,0 +1,50 @@
+package dbx
+
+import "errors"
+
+var (
+	// ErrNoColumns is returned when a query returns no columns.
+	ErrNoColumns = errors.New("query returned no columns")
+
+	// ErrUnknownColumn is returned when a query returns a column that does not match any field of a struct to scan into.
+	ErrUnknownColumn = errors.New("unknown column")
+
+	// ErrUnknownParameter is returned when a named parameter does not match any field or key of the argument.
+	ErrUnknownParameter = errors.New("unknown parameter")
+
+	// ErrEmptySlice is returned by In when a slice argument is empty.
+	ErrEmptySlice = errors.New("empty slice argument")
+
+	// ErrNoResultSet is returned when a query returns fewer result sets than expected.
+	ErrNoResultSet = errors.New("no result set")
+
+	// ErrNoRowsAffected is returned when a statement affects fewer rows than expected.
+	ErrNoRowsAffected = errors.New("no rows affected")
+
+	// ErrReusedTransactionCommit is returned when the operation attempts to commit or roll back a transaction it does not own.
+	ErrReusedTransactionCommit = errors.New("transaction is committed or rolled back by its owner")
+
+	// ErrStatementLimitExceeded is returned when the operation exceeds the number of statements allowed via WithMaxStatements.
+	ErrStatementLimitExceeded = errors.New("statement limit exceeded")
+
+	// ErrQueryNotAllowed is returned when a query is not in the allowlist.
+	ErrQueryNotAllowed = errors.New("query is not allowed")
+
+	// ErrCommit is returned when a transaction fails to commit.
+	ErrCommit = errors.New("failed to commit transaction")
+
+	// ErrRollback is returned along with the operation error when a transaction fails to roll back.
+	ErrRollback = errors.New("failed to roll back transaction")
+
+	// ErrReleaseSavepoint is returned when a savepoint of a nested operation fails to be released.
+	ErrReleaseSavepoint = errors.New("failed to release savepoint")
+
+	// ErrTooManyTransactions is returned when the limit of concurrent transactions set via WithMaxConcurrentTransactions is reached.
+	ErrTooManyTransactions = errors.New("too many concurrent transactions")
+
+	// ErrInvalidInterval is returned when WaitReady is called with a non-positive interval.
+	ErrInvalidInterval = errors.New("interval must be positive")
+
+	// ErrConflictingOptions is returned when mutually exclusive transaction options are used together.
+	ErrConflictingOptions = errors.New("conflicting transaction options")
+)
@@ -0,0 +1,33 @@
+package dbx
+
+import "fmt"
+
+// ExecExpectingRows executes a given statement and returns ErrNoRowsAffected if it affects fewer than minRows rows.
+// It helps to catch updates and deletes that silently match nothing, e.g. due to a stale id.
+// If the driver does not report affected rows, the error returned by sql.Result.RowsAffected is returned.
+// Passing minRows <= 0 opts out of the check.
+func ExecExpectingRows(ctx Context, minRows int64, query string, args ...interface{}) (Result, error) {
+	out, err := ctx.Executor().ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{out}
+
+	if minRows <= 0 {
+		return res, nil
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		return res, err
+	}
+
+	if affected < minRows {
+		return res, fmt.Errorf("%w: expected at least %d, got %d", ErrNoRowsAffected, minRows, affected)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestExecExpectingRows(test *testing.T) {
+	test.Run("should return result when enough rows affected", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res, err := dbx.ExecExpectingRows(db.Context(context.Background()), 1, "UPDATE users SET name = 'John' WHERE id = ?", 1)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should fail when no rows affected", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WithArgs(42).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := dbx.ExecExpectingRows(db.Context(context.Background()), 1, "UPDATE users SET name = 'John' WHERE id = ?", 42)
+
+		assert.ErrorIs(t, err, dbx.ErrNoRowsAffected)
+	})
+
+	test.Run("should return driver error when affected rows are not supported", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("not supported")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewErrorResult(testErr))
+
+		_, err := dbx.ExecExpectingRows(db.Context(context.Background()), 1, "UPDATE users SET name = 'John'")
+
+		assert.Equal(t, testErr, err)
+	})
+
+	test.Run("should skip check when minRows is zero", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewErrorResult(errors.New("not supported")))
+
+		_, err := dbx.ExecExpectingRows(db.Context(context.Background()), 0, "UPDATE users SET name = 'John'")
+
+		assert.NoError(t, err)
+	})
+}
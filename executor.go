@@ -0,0 +1,64 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+type (
+	// errorExecutor is an executor that fails every call with a given error.
+	errorExecutor struct {
+		err error
+	}
+
+	// errorConnector is a connector that fails every connection attempt with a given error.
+	errorConnector struct {
+		err error
+	}
+)
+
+// newErrorRow returns a row that returns a given error on Scan.
+// sql.Row cannot be constructed outside of database/sql, so the row is produced by a database that fails to connect.
+func newErrorRow(err error) *sql.Row {
+	db := sql.OpenDB(&errorConnector{err})
+	defer db.Close()
+
+	return db.QueryRowContext(context.Background(), "")
+}
+
+func (c *errorConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (c *errorConnector) Driver() driver.Driver {
+	return c
+}
+
+func (c *errorConnector) Open(_ string) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (e *errorExecutor) Exec(_ string, _ ...interface{}) (sql.Result, error) {
+	return nil, e.err
+}
+
+func (e *errorExecutor) Query(_ string, _ ...interface{}) (*sql.Rows, error) {
+	return nil, e.err
+}
+
+func (e *errorExecutor) QueryRow(_ string, _ ...interface{}) *sql.Row {
+	return newErrorRow(e.err)
+}
+
+func (e *errorExecutor) ExecContext(_ context.Context, _ string, _ ...interface{}) (sql.Result, error) {
+	return nil, e.err
+}
+
+func (e *errorExecutor) QueryContext(_ context.Context, _ string, _ ...interface{}) (*sql.Rows, error) {
+	return nil, e.err
+}
+
+func (e *errorExecutor) QueryRowContext(_ context.Context, _ string, _ ...interface{}) *sql.Row {
+	return newErrorRow(e.err)
+}
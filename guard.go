@@ -0,0 +1,34 @@
+package dbx
+
+// CommitGuard defines how a transaction created by Transaction or TransactionWithResult
+// handles Commit and Rollback calls made by the code it is passed to.
+type CommitGuard int
+
+const (
+	// CommitGuardNoop ignores Commit and Rollback calls.
+	CommitGuardNoop CommitGuard = iota
+	// CommitGuardError makes Commit and Rollback calls return ErrReusedTransactionCommit.
+	CommitGuardError
+)
+
+// guardedTransactor prevents a transaction from being committed or rolled back by anyone except its owner.
+type guardedTransactor struct {
+	Transactor
+	mode CommitGuard
+}
+
+func (g *guardedTransactor) Commit() error {
+	return g.guard()
+}
+
+func (g *guardedTransactor) Rollback() error {
+	return g.guard()
+}
+
+func (g *guardedTransactor) guard() error {
+	if g.mode == CommitGuardError {
+		return ErrReusedTransactionCommit
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package dbx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestCommitGuard(test *testing.T) {
+	test.Run("should ignore commit of reused transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectExec("SELECT 2").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c1 dbx.Context) error {
+			err := dbx.Transaction(c1, db, func(c2 dbx.Context) error {
+				if _, e := c2.Executor().Exec("SELECT 1"); e != nil {
+					return e
+				}
+
+				return c2.Executor().(dbx.Transactor).Commit()
+			})
+
+			if err != nil {
+				return err
+			}
+
+			_, err = c1.Executor().Exec("SELECT 2")
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should fail commit of reused transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c1 dbx.Context) error {
+			return dbx.Transaction(c1, db, func(c2 dbx.Context) error {
+				return c2.Executor().(dbx.Transactor).Commit()
+			})
+		}, dbx.WithCommitGuard(dbx.CommitGuardError))
+
+		assert.ErrorIs(t, err, dbx.ErrReusedTransactionCommit)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
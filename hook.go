@@ -0,0 +1,223 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"unicode/utf8"
+)
+
+type (
+	// statementKind is a kind of executor method a statement is executed with.
+	statementKind int
+
+	// statement describes a statement passed to a hook.
+	statement struct {
+		kind  statementKind
+		query string
+		args  []interface{}
+		// rows points to the rows returned by a query once next returns, it is nil for other kinds.
+		rows **sql.Rows
+		// maxLength is the length the query is truncated to by logged, 0 means no limit.
+		maxLength int
+	}
+
+	// hook wraps the execution of every statement of an executor.
+	// It must call next to execute the statement, passing a context to execute it with, and return its error.
+	// Non-context executor methods are passed context.Background() and ignore the context passed to next.
+	hook func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error
+
+	// hookedExecutor is an executor that passes every statement through a hook.
+	hookedExecutor struct {
+		exec Executor
+		hook hook
+		// maxLength is the length of the queries emitted by the hook, set via WithMaxLoggedQueryLength.
+		maxLength int
+	}
+
+	// hookedTransactor is a hooked executor that keeps the transaction methods of the underlying executor.
+	hookedTransactor struct {
+		*hookedExecutor
+		tx Transactor
+	}
+
+	// internalKey marks the context of statements generated by dbx itself, e.g. savepoint statements.
+	internalKey struct{}
+)
+
+const (
+	kindExec statementKind = iota
+	kindQuery
+	kindQueryRow
+)
+
+func (k statementKind) String() string {
+	switch k {
+	case kindExec:
+		return "exec"
+	case kindQuery:
+		return "query"
+	default:
+		return "queryrow"
+	}
+}
+
+// withHook wraps a given executor with a given hook.
+// If the executor is a Transactor, the returned executor is a Transactor as well, so that Transaction keeps reusing it.
+func withHook(exec Executor, h hook) Executor {
+	return withLimitedHook(exec, h, 0)
+}
+
+// withLimitedHook wraps a given executor with a given hook that emits queries truncated to a given length.
+func withLimitedHook(exec Executor, h hook, maxLength int) Executor {
+	hooked := &hookedExecutor{exec, h, maxLength}
+
+	if tx, ok := exec.(Transactor); ok {
+		return &hookedTransactor{hooked, tx}
+	}
+
+	return hooked
+}
+
+// withInternal returns a context that marks the statements executed with it as generated by dbx.
+func withInternal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalKey{}, true)
+}
+
+// isInternal reports whether a statement executed with a given context is generated by dbx.
+func isInternal(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalKey{}).(bool)
+
+	return internal
+}
+
+// inheritHooks wraps a given transaction with the hooks of a given executor,
+// so that transactions started from a hooked context are hooked as well.
+func inheritHooks(from Executor, tx Transactor) Transactor {
+	switch exec := from.(type) {
+	case *hookedExecutor:
+		return withLimitedHook(inheritHooks(exec.exec, tx), exec.hook, exec.maxLength).(Transactor)
+	case *hookedTransactor:
+		return withLimitedHook(inheritHooks(exec.exec, tx), exec.hook, exec.maxLength).(Transactor)
+	default:
+		return tx
+	}
+}
+
+// WithMaxLoggedQueryLength returns a copy of a given executor whose hooks, e.g. WithLogging, WithTracing, WithSlowQueryHook,
+// WithRowsLeakDetection and the errors of WithQueryAllowlist, emit queries truncated to n characters followed by an ellipsis.
+// It applies to the hooks the executor is already wrapped with, so it is meant to wrap the executor last.
+// The executed queries and the allowlist checks are not affected. A non-positive length disables the truncation.
+func WithMaxLoggedQueryLength(exec Executor, n int) Executor {
+	switch hooked := exec.(type) {
+	case *hookedExecutor:
+		return withLimitedHook(WithMaxLoggedQueryLength(hooked.exec, n), hooked.hook, n)
+	case *hookedTransactor:
+		return withLimitedHook(WithMaxLoggedQueryLength(hooked.exec, n), hooked.hook, n)
+	default:
+		return exec
+	}
+}
+
+// logged returns the query to be emitted by a hook.
+func (s statement) logged() string {
+	return truncateQuery(s.query, s.maxLength)
+}
+
+// truncateQuery truncates a given query to a given number of characters followed by an ellipsis.
+func truncateQuery(query string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(query) <= maxLength {
+		return query
+	}
+
+	return string([]rune(query)[:maxLength]) + "..."
+}
+
+// unwrapHooks returns the executor underlying a given possibly hooked executor.
+func unwrapHooks(exec Executor) Executor {
+	for {
+		switch hooked := exec.(type) {
+		case *hookedExecutor:
+			exec = hooked.exec
+		case *hookedTransactor:
+			exec = hooked.exec
+		default:
+			return exec
+		}
+	}
+}
+
+func (e *hookedExecutor) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	err = e.hook(context.Background(), statement{kindExec, query, args, nil, e.maxLength}, func(_ context.Context) error {
+		res, err = e.exec.Exec(query, args...)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (e *hookedExecutor) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
+	err = e.hook(context.Background(), statement{kindQuery, query, args, &rows, e.maxLength}, func(_ context.Context) error {
+		rows, err = e.exec.Query(query, args...)
+
+		return err
+	})
+
+	return rows, err
+}
+
+func (e *hookedExecutor) QueryRow(query string, args ...interface{}) (row *sql.Row) {
+	err := e.hook(context.Background(), statement{kindQueryRow, query, args, nil, e.maxLength}, func(_ context.Context) error {
+		row = e.exec.QueryRow(query, args...)
+
+		return row.Err()
+	})
+
+	if row == nil {
+		return newErrorRow(err)
+	}
+
+	return row
+}
+
+func (e *hookedExecutor) ExecContext(dbContext context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	err = e.hook(dbContext, statement{kindExec, query, args, nil, e.maxLength}, func(ctx context.Context) error {
+		res, err = e.exec.ExecContext(ctx, query, args...)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (e *hookedExecutor) QueryContext(dbContext context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	err = e.hook(dbContext, statement{kindQuery, query, args, &rows, e.maxLength}, func(ctx context.Context) error {
+		rows, err = e.exec.QueryContext(ctx, query, args...)
+
+		return err
+	})
+
+	return rows, err
+}
+
+func (e *hookedExecutor) QueryRowContext(dbContext context.Context, query string, args ...interface{}) (row *sql.Row) {
+	err := e.hook(dbContext, statement{kindQueryRow, query, args, nil, e.maxLength}, func(ctx context.Context) error {
+		row = e.exec.QueryRowContext(ctx, query, args...)
+
+		return row.Err()
+	})
+
+	if row == nil {
+		return newErrorRow(err)
+	}
+
+	return row
+}
+
+func (t *hookedTransactor) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *hookedTransactor) Rollback() error {
+	return t.tx.Rollback()
+}
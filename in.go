@@ -0,0 +1,97 @@
+package dbx
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// In expands ? placeholders whose arguments are slices into as many placeholders as the slices have elements
+// and flattens the arguments accordingly, e.g. "id IN (?)" with []int{1, 2, 3} becomes "id IN (?, ?, ?)" with 1, 2, 3.
+// Other arguments, including byte slices and driver.Valuer implementations, are left untouched.
+// An empty slice results in ErrEmptySlice, since "IN ()" is not valid SQL.
+// Placeholders within quoted strings and comments are ignored.
+func In(query string, args ...interface{}) (string, []interface{}, error) {
+	var sb strings.Builder
+
+	out := make([]interface{}, 0, len(args))
+	n := 0
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		end := i + 1
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end = skipQuoted(query, i)
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end = skipLineComment(query, i)
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end = skipBlockComment(query, i)
+		case c == '?':
+			if n >= len(args) {
+				return "", nil, fmt.Errorf("query has more placeholders than %d arguments", len(args))
+			}
+
+			arg := args[n]
+			n++
+
+			values, ok := expandable(arg)
+
+			if !ok {
+				sb.WriteByte('?')
+				out = append(out, arg)
+				i = end
+
+				continue
+			}
+
+			if values.Len() == 0 {
+				return "", nil, fmt.Errorf("%w: argument %d", ErrEmptySlice, n)
+			}
+
+			for j := 0; j < values.Len(); j++ {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+
+				sb.WriteByte('?')
+				out = append(out, values.Index(j).Interface())
+			}
+
+			i = end
+
+			continue
+		}
+
+		sb.WriteString(query[i:end])
+		i = end
+	}
+
+	if n != len(args) {
+		return "", nil, fmt.Errorf("query has %d placeholders, but %d arguments are passed", n, len(args))
+	}
+
+	return sb.String(), out, nil
+}
+
+// expandable returns a given argument as a slice if it should be expanded into multiple placeholders.
+func expandable(arg interface{}) (reflect.Value, bool) {
+	if _, ok := arg.(driver.Valuer); ok {
+		return reflect.Value{}, false
+	}
+
+	value := reflect.ValueOf(arg)
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return reflect.Value{}, false
+	}
+
+	// byte slices are passed to drivers as is
+	if value.Type().Elem().Kind() == reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+
+	return value, true
+}
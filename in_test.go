@@ -0,0 +1,43 @@
+package dbx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestIn(test *testing.T) {
+	test.Run("should expand slice arguments", func(t *testing.T) {
+		query, args, err := dbx.In(
+			"SELECT * FROM users WHERE id IN (?) AND status = ? AND role IN (?) AND note <> '?'",
+			[]int{1, 2, 3}, "active", []string{"admin"},
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE id IN (?, ?, ?) AND status = ? AND role IN (?) AND note <> '?'", query)
+		assert.Equal(t, []interface{}{1, 2, 3, "active", "admin"}, args)
+	})
+
+	test.Run("should not expand byte slices", func(t *testing.T) {
+		query, args, err := dbx.In("UPDATE files SET data = ? WHERE id IN (?)", []byte("data"), [2]int64{1, 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE files SET data = ? WHERE id IN (?, ?)", query)
+		assert.Equal(t, []interface{}{[]byte("data"), int64(1), int64(2)}, args)
+	})
+
+	test.Run("should fail on empty slice", func(t *testing.T) {
+		_, _, err := dbx.In("SELECT * FROM users WHERE id IN (?)", []int{})
+
+		assert.ErrorIs(t, err, dbx.ErrEmptySlice)
+	})
+
+	test.Run("should fail on mismatched arguments", func(t *testing.T) {
+		_, _, err := dbx.In("SELECT * FROM users WHERE id IN (?)")
+		assert.Error(t, err)
+
+		_, _, err = dbx.In("SELECT * FROM users WHERE id IN (?)", []int{1}, 2)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+package dbx
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// numericTypes are database type names whose textual values are written as JSON numbers.
+var numericTypes = map[string]bool{
+	"INT":       true,
+	"INT2":      true,
+	"INT4":      true,
+	"INT8":      true,
+	"INTEGER":   true,
+	"TINYINT":   true,
+	"SMALLINT":  true,
+	"MEDIUMINT": true,
+	"BIGINT":    true,
+	"DECIMAL":   true,
+	"NUMERIC":   true,
+	"FLOAT":     true,
+	"FLOAT4":    true,
+	"FLOAT8":    true,
+	"DOUBLE":    true,
+	"REAL":      true,
+}
+
+// QueryJSON executes a given query and streams its result to a given writer as a JSON array of objects keyed by column names.
+// NULL values are written as null. Values that drivers return as bytes are written as numbers for numeric columns
+// and as strings otherwise, while other values are encoded as is.
+func QueryJSON(ctx Context, w io.Writer, query string, args ...interface{}) error {
+	rows, err := ctx.Executor().QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+
+	if err != nil {
+		return err
+	}
+
+	keys := make([][]byte, len(types))
+	numeric := make([]bool, len(types))
+
+	for i, typ := range types {
+		keys[i], err = json.Marshal(typ.Name())
+
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(strings.ToUpper(typ.DatabaseTypeName()), "UNSIGNED ")
+		numeric[i] = numericTypes[name]
+	}
+
+	values := make([]interface{}, len(types))
+	dest := make([]interface{}, len(types))
+
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteByte('[')
+
+	for n := 0; rows.Next(); n++ {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		if n > 0 {
+			out.WriteByte(',')
+		}
+
+		out.WriteByte('{')
+
+		for i, value := range values {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			encoded, err := encodeJSON(value, numeric[i])
+
+			if err != nil {
+				return err
+			}
+
+			out.Write(keys[i])
+			out.WriteByte(':')
+			out.Write(encoded)
+		}
+
+		out.WriteByte('}')
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	out.WriteByte(']')
+
+	return out.Flush()
+}
+
+func encodeJSON(value interface{}, numeric bool) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case []byte:
+		if numeric && json.Valid(v) {
+			return v, nil
+		}
+
+		return json.Marshal(string(v))
+	default:
+		return json.Marshal(v)
+	}
+}
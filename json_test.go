@@ -0,0 +1,52 @@
+package dbx_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestQueryJSON(test *testing.T) {
+	test.Run("should stream rows as array of objects", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRowsWithColumnDefinition(
+				sqlmock.NewColumn("id").OfType("BIGINT", int64(0)),
+				sqlmock.NewColumn("price").OfType("NUMERIC", []byte{}),
+				sqlmock.NewColumn("name").OfType("VARCHAR", []byte{}).Nullable(true),
+			).
+				AddRow(int64(1), []byte("9.99"), []byte("John \"Doe\"")).
+				AddRow(int64(2), []byte("10"), nil),
+		).RowsWillBeClosed()
+
+		var sb strings.Builder
+
+		err := dbx.QueryJSON(db.Context(context.Background()), &sb, "SELECT id, price, name FROM products")
+
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"id":1,"price":9.99,"name":"John \"Doe\""},{"id":2,"price":10,"name":null}]`, sb.String())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should write empty array when there are no rows", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		var sb strings.Builder
+
+		err := dbx.QueryJSON(db.Context(context.Background()), &sb, "SELECT id FROM products")
+
+		assert.NoError(t, err)
+		assert.Equal(t, `[]`, sb.String())
+	})
+}
@@ -0,0 +1,57 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// trackedRows holds the addresses of rows that have a leak detection finalizer set.
+// Addresses are used instead of the rows themselves, so that the rows can still be collected.
+var trackedRows sync.Map
+
+// WithRowsLeakDetection wraps a given executor, so that rows returned by its queries are checked when garbage collected.
+// If the rows were neither closed nor fully iterated, a given function is called with the query and the stack trace of the call
+// that executed it. Detection relies on finalizers, so leaks are reported with a delay and only once the rows are collected.
+// It captures a stack trace on every query, so it is intended for development and tests rather than production.
+// Note that rows of a query executed with a cancellable context are referenced by database/sql until the context is done.
+// If detectors are composed, e.g. an executor is wrapped twice, only the innermost one tracks the rows.
+func WithRowsLeakDetection(exec Executor, report func(query string, stack []byte)) Executor {
+	return withHook(exec, func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error {
+		if stmt.rows == nil {
+			return next(ctx)
+		}
+
+		query := stmt.logged()
+		stack := debug.Stack()
+		err := next(ctx)
+
+		rows := *stmt.rows
+
+		if rows == nil {
+			return err
+		}
+
+		addr := reflect.ValueOf(rows).Pointer()
+
+		// a finalizer can be set only once, so rows already tracked by another detector are skipped
+		if _, tracked := trackedRows.LoadOrStore(addr, struct{}{}); tracked {
+			return err
+		}
+
+		// the finalizer must not reference the rows, otherwise they are never collected
+		runtime.SetFinalizer(rows, func(rows *sql.Rows) {
+			trackedRows.Delete(addr)
+
+			// closed rows return an error
+			if _, err := rows.Columns(); err == nil {
+				report(query, stack)
+			}
+		})
+
+		return err
+	})
+}
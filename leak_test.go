@@ -0,0 +1,92 @@
+package dbx_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestWithRowsLeakDetection(test *testing.T) {
+	collect := func(leaks chan string) string {
+		for i := 0; i < 100; i++ {
+			runtime.GC()
+
+			select {
+			case query := <-leaks:
+				return query
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		return ""
+	}
+
+	test.Run("should report unclosed rows", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+		leaks := make(chan string, 1)
+		exec := dbx.WithRowsLeakDetection(db, func(query string, stack []byte) {
+			assert.Contains(t, string(stack), "leak_test.go")
+
+			leaks <- query
+		})
+
+		func() {
+			_, err := exec.Query("SELECT name FROM users")
+			assert.NoError(t, err)
+		}()
+
+		assert.Equal(t, "SELECT name FROM users", collect(leaks))
+	})
+
+	test.Run("should not report closed rows", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+		leaks := make(chan string, 1)
+		exec := dbx.WithRowsLeakDetection(db, func(query string, stack []byte) {
+			leaks <- query
+		})
+
+		func() {
+			rows, err := exec.Query("SELECT name FROM users")
+			assert.NoError(t, err)
+			assert.NoError(t, rows.Close())
+		}()
+
+		assert.Equal(t, "", collect(leaks))
+	})
+
+	test.Run("should report once when composed", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+		leaks := make(chan string, 2)
+		report := func(query string, stack []byte) {
+			leaks <- query
+		}
+		exec := dbx.WithRowsLeakDetection(dbx.WithRowsLeakDetection(db, report), report)
+
+		func() {
+			_, err := exec.Query("SELECT name FROM users")
+			assert.NoError(t, err)
+		}()
+
+		assert.Equal(t, "SELECT name FROM users", collect(leaks))
+		assert.Equal(t, "", collect(leaks))
+	})
+}
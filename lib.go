@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"io"
+	"time"
 )
 
 type (
@@ -31,12 +32,30 @@ type (
 		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
 	}
 
+	// Connector provides an abstraction for acquiring a dedicated connection from sql.DB
+	Connector interface {
+		Conn(ctx context.Context) (*sql.Conn, error)
+	}
+
 	// ContextCreator provides an executor context creation.
 	ContextCreator interface {
 		// Context creates a new executor context
 		Context(ctx context.Context) Context
 	}
 
+	// TransactionManager manages the lifecycle of transactions created by Transaction and TransactionWithResult.
+	// It allows to integrate with external transaction coordinators, e.g. to implement two-phase commit.
+	TransactionManager interface {
+		// Begin starts a new transaction using a given beginner.
+		Begin(ctx context.Context, db Beginner, opts *sql.TxOptions) (Transactor, error)
+
+		// Commit commits a given transaction.
+		Commit(ctx context.Context, tx Transactor) error
+
+		// Rollback rolls back a given transaction.
+		Rollback(ctx context.Context, tx Transactor) error
+	}
+
 	// Operation is a user-defined database operation that needs to be performed within a transaction
 	Operation func(ctx Context) error
 
@@ -48,7 +67,34 @@ type (
 		io.Closer
 		ContextCreator
 		Beginner
+		Connector
 		Executor
+
+		// DriverName returns the name of the underlying driver.
+		DriverName() string
+
+		// Ping verifies that the database is reachable, establishing a connection if necessary.
+		Ping() error
+
+		// PingContext verifies that the database is reachable, establishing a connection if necessary.
+		PingContext(ctx context.Context) error
+
+		// Stats returns the connection pool statistics.
+		Stats() sql.DBStats
+
+		// SetMaxOpenConns sets the maximum number of open connections, see sql.DB.SetMaxOpenConns.
+		SetMaxOpenConns(n int)
+
+		// SetMaxIdleConns sets the maximum number of idle connections, see sql.DB.SetMaxIdleConns.
+		SetMaxIdleConns(n int)
+
+		// SetConnMaxLifetime sets the maximum amount of time a connection may be reused, see sql.DB.SetConnMaxLifetime.
+		SetConnMaxLifetime(d time.Duration)
+
+		// WaitReady pings the database in a loop with a given interval until it succeeds or the context is done.
+		// On context expiration, the last ping error is returned.
+		// The interval must be positive, otherwise ErrInvalidInterval is returned.
+		WaitReady(ctx context.Context, interval time.Duration) error
 	}
 
 	// Context provides a general purpose abstraction to communication between domain services and data repositories.
@@ -56,7 +102,21 @@ type (
 		context.Context
 
 		// Executor returns a sql executor.
-		// If transaction provided, sql.Tx will be returned, otherwise sql.DB.
+		// Within a transaction, a Transactor is returned, otherwise the database or the connection the context is bound to.
+		// Transactions created by Transaction or TransactionWithResult are not *sql.Tx, so type assertions should target Transactor.
 		Executor() Executor
+
+		// Cancel cancels the transaction the context belongs to with a given cause, which makes database/sql roll it back.
+		// The operation should return promptly after the cancellation.
+		// It is a no-op for contexts that are not created by Transaction or TransactionWithResult.
+		Cancel(cause error)
+
+		// IsActive reports whether the transaction the context belongs to is neither committed nor rolled back yet.
+		// It always returns true for contexts that are not created by Transaction or TransactionWithResult.
+		IsActive() bool
+
+		// WithDeadline returns a child context with a given deadline that shares the executor of the context.
+		// Unlike context.WithDeadline, the returned context is a Context, so it can be used within the same transaction.
+		WithDeadline(d time.Time) (Context, context.CancelFunc)
 	}
 )
@@ -0,0 +1,63 @@
+package dbx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TransactionLimitMode defines how a transaction is started once the limit set via WithMaxConcurrentTransactions is reached.
+type TransactionLimitMode int
+
+const (
+	// TransactionLimitWait waits for a running transaction to finish until the context is done.
+	TransactionLimitWait TransactionLimitMode = iota
+	// TransactionLimitFail fails with ErrTooManyTransactions right away.
+	TransactionLimitFail
+)
+
+// transactionLimiter is implemented by databases that limit the number of concurrent transactions.
+type transactionLimiter interface {
+	// acquireTransaction reserves a slot for a new transaction and returns a function that frees it.
+	acquireTransaction(ctx context.Context) (func(), error)
+}
+
+// semaphore limits the number of concurrent transactions.
+type semaphore struct {
+	slots chan struct{}
+	mode  TransactionLimitMode
+}
+
+func newSemaphore(n int, mode TransactionLimitMode) *semaphore {
+	return &semaphore{make(chan struct{}, n), mode}
+}
+
+func (s *semaphore) acquire(ctx context.Context) (func(), error) {
+	select {
+	case s.slots <- struct{}{}:
+		return s.releaser(), nil
+	default:
+	}
+
+	if s.mode == TransactionLimitFail {
+		return nil, ErrTooManyTransactions
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		return s.releaser(), nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrTooManyTransactions, ctx.Err())
+	}
+}
+
+// releaser returns a function that frees the acquired slot once, no matter how many times it is called.
+func (s *semaphore) releaser() func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			<-s.slots
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package dbx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestWithMaxConcurrentTransactions(test *testing.T) {
+	test.Run("should fail when limit is reached", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock, dbx.WithMaxConcurrentTransactions(1, dbx.TransactionLimitFail))
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+				return nil
+			})
+
+			assert.ErrorIs(t, err, dbx.ErrTooManyTransactions)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+
+		// the slot is freed once the transaction is finished
+		err = dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should wait until context is done", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock, dbx.WithMaxConcurrentTransactions(1, dbx.TransactionLimitWait))
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+				return nil
+			})
+
+			assert.ErrorIs(t, err, dbx.ErrTooManyTransactions)
+			assert.ErrorIs(t, err, context.Canceled)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should wait for running transaction to finish", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock, dbx.WithMaxConcurrentTransactions(1, dbx.TransactionLimitWait))
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		started := make(chan struct{})
+		done := make(chan error)
+
+		go func() {
+			<-started
+
+			done <- dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+				return nil
+			})
+		}()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			close(started)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, <-done)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not limit reused transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock, dbx.WithMaxConcurrentTransactions(1, dbx.TransactionLimitFail))
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				return nil
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
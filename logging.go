@@ -0,0 +1,24 @@
+package dbx
+
+import (
+	"context"
+	"time"
+)
+
+// Logger receives a statement executed by an executor along with its duration and error.
+type Logger func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error)
+
+// WithLogging wraps a given executor, so that every statement it executes is passed to a given logger once it completes.
+// The results and errors of the statements are returned unchanged.
+// Non-context executor methods pass context.Background() to the logger.
+// Transactions started from a context bound to the returned executor are logged as well.
+func WithLogging(exec Executor, logger Logger) Executor {
+	return withHook(exec, func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error {
+		started := time.Now()
+		err := next(ctx)
+
+		logger(ctx, stmt.logged(), stmt.args, time.Since(started), err)
+
+		return err
+	})
+}
@@ -0,0 +1,129 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+type logEntry struct {
+	query string
+	args  []interface{}
+	err   error
+}
+
+func TestWithLogging(test *testing.T) {
+	test.Run("should log statements with their errors", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectQuery("SELECT name").WillReturnError(testErr)
+
+		var entries []logEntry
+
+		exec := dbx.WithLogging(db, func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+			entries = append(entries, logEntry{query, args, err})
+		})
+
+		res, err := exec.ExecContext(context.Background(), "UPDATE users SET active = true WHERE id = ?", 1)
+		assert.NoError(t, err)
+
+		affected, _ := res.RowsAffected()
+		assert.Equal(t, int64(1), affected)
+
+		_, err = exec.Query("SELECT name FROM users")
+		assert.Equal(t, testErr, err)
+
+		assert.Equal(t, []logEntry{
+			{"UPDATE users SET active = true WHERE id = ?", []interface{}{1}, nil},
+			{"SELECT name FROM users", nil, testErr},
+		}, entries)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should log statements of transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("DELETE FROM sessions").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectCommit()
+
+		var queries []string
+
+		ctx := dbx.NewContext(context.Background(), dbx.WithLogging(db, func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+			queries = append(queries, query)
+		}))
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			_, err := c.Executor().ExecContext(c, "DELETE FROM sessions")
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"DELETE FROM sessions"}, queries)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithMaxLoggedQueryLength(test *testing.T) {
+	test.Run("should truncate emitted queries", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users SET active = true WHERE id IN").WillReturnResult(sqlmock.NewResult(0, 3))
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		var queries []string
+
+		exec := dbx.WithMaxLoggedQueryLength(dbx.WithLogging(db, func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+			queries = append(queries, query)
+		}), 12)
+
+		_, err := exec.Exec("UPDATE users SET active = true WHERE id IN (1, 2, 3)")
+		assert.NoError(t, err)
+
+		_, err = exec.Exec("UPDATE users")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"UPDATE users...", "UPDATE users"}, queries)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should truncate queries of transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 3))
+		dmock.ExpectCommit()
+
+		var queries []string
+
+		exec := dbx.WithMaxLoggedQueryLength(dbx.WithLogging(db, func(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+			queries = append(queries, query)
+		}), 12)
+
+		err := dbx.Transaction(dbx.NewContext(context.Background(), exec), db, func(c dbx.Context) error {
+			_, err := c.Executor().Exec("UPDATE users SET active = true")
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"UPDATE users..."}, queries)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,31 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type defaultTransactionManager struct{}
+
+// DefaultTransactionManager returns a transaction manager that delegates to the Beginner and Transactor methods.
+func DefaultTransactionManager() TransactionManager {
+	return defaultTransactionManager{}
+}
+
+func (defaultTransactionManager) Begin(ctx context.Context, db Beginner, opts *sql.TxOptions) (Transactor, error) {
+	tx, err := db.BeginTx(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (defaultTransactionManager) Commit(_ context.Context, tx Transactor) error {
+	return tx.Commit()
+}
+
+func (defaultTransactionManager) Rollback(_ context.Context, tx Transactor) error {
+	return tx.Rollback()
+}
@@ -0,0 +1,108 @@
+package dbx_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+type recordingManager struct {
+	calls []string
+	tx    dbx.Transactor
+}
+
+func (m *recordingManager) Begin(ctx context.Context, db dbx.Beginner, opts *sql.TxOptions) (dbx.Transactor, error) {
+	m.calls = append(m.calls, "begin")
+
+	tx, err := dbx.DefaultTransactionManager().Begin(ctx, db, opts)
+	m.tx = tx
+
+	return tx, err
+}
+
+func (m *recordingManager) Commit(ctx context.Context, tx dbx.Transactor) error {
+	m.calls = append(m.calls, "commit")
+
+	if tx != m.tx {
+		return errors.New("unexpected transaction")
+	}
+
+	return dbx.DefaultTransactionManager().Commit(ctx, tx)
+}
+
+func (m *recordingManager) Rollback(ctx context.Context, tx dbx.Transactor) error {
+	m.calls = append(m.calls, "rollback")
+
+	if tx != m.tx {
+		return errors.New("unexpected transaction")
+	}
+
+	return dbx.DefaultTransactionManager().Rollback(ctx, tx)
+}
+
+func TestTransactionManager(test *testing.T) {
+	test.Run("should delegate begin and commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		manager := &recordingManager{}
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			_, e := c.Executor().Exec("SELECT 1")
+
+			return e
+		}, dbx.WithTransactionManager(manager))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"begin", "commit"}, manager.calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should delegate begin and rollback", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		manager := &recordingManager{}
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return testErr
+		}, dbx.WithTransactionManager(manager))
+
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, []string{"begin", "rollback"}, manager.calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not delegate reused transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		manager := &recordingManager{}
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				return nil
+			}, dbx.WithTransactionManager(manager))
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, manager.calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
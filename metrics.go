@@ -0,0 +1,116 @@
+package dbx
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	// MetricsSink receives measurements of statements executed by an executor created by WithMetrics,
+	// e.g. to update a counter and a latency histogram of a metrics library.
+	MetricsSink interface {
+		// ObserveStatement is called once a statement completes.
+		// The operation is "exec", "query" or "queryrow" and the outcome is "ok" or "error".
+		// The statement itself is not passed, since using it as a label would explode the cardinality.
+		// The name of the operation set via Named is available via OperationName.
+		ObserveStatement(ctx context.Context, operation, outcome string, duration time.Duration)
+	}
+
+	// MetricsOptions configures an executor created by WithMetrics.
+	// Either a custom sink or Prometheus collectors receive the measurements.
+	MetricsOptions struct {
+		// Sink receives the measurements. It takes precedence over the Prometheus collectors.
+		Sink MetricsSink
+		// Registerer registers the Prometheus collectors that are not provided,
+		// the dbx_statements_total counter and the dbx_statement_duration_seconds histogram.
+		Registerer prometheus.Registerer
+		// Statements is a pre-built counter of statements with the "operation", "outcome" and "name" labels.
+		Statements *prometheus.CounterVec
+		// Duration is a pre-built histogram of statement durations in seconds with the "operation", "outcome" and "name" labels.
+		Duration *prometheus.HistogramVec
+	}
+
+	// prometheusSink reports measurements to Prometheus collectors.
+	prometheusSink struct {
+		statements *prometheus.CounterVec
+		duration   *prometheus.HistogramVec
+	}
+)
+
+// metricsLabels are the labels of the Prometheus collectors, where name is the operation name set via Named.
+var metricsLabels = []string{"operation", "outcome", "name"}
+
+// WithMetrics wraps a given executor, so that the operation type, outcome and duration of every statement it executes
+// are reported to the sink set in a given options.
+// Transactions started from a context bound to the returned executor are measured as well.
+// Without a custom sink, the measurements are reported to the Prometheus collectors set in the options.
+// Missing collectors are created and registered if a registerer is set, which panics if the registration fails, as promauto does.
+// If neither a sink nor collectors are set, the executor is returned as is.
+func WithMetrics(exec Executor, opts MetricsOptions) Executor {
+	if opts.Sink == nil {
+		opts.Sink = newPrometheusSink(opts)
+	}
+
+	if opts.Sink == nil {
+		return exec
+	}
+
+	return withHook(exec, func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error {
+		started := time.Now()
+		err := next(ctx)
+		outcome := "ok"
+
+		if err != nil {
+			outcome = "error"
+		}
+
+		opts.Sink.ObserveStatement(ctx, stmt.kind.String(), outcome, time.Since(started))
+
+		return err
+	})
+}
+
+// newPrometheusSink returns a sink that reports to the collectors set in a given options,
+// or nil if there are none and no registerer to create them.
+func newPrometheusSink(opts MetricsOptions) MetricsSink {
+	sink := &prometheusSink{opts.Statements, opts.Duration}
+
+	if opts.Registerer != nil && sink.statements == nil {
+		sink.statements = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dbx_statements_total",
+			Help: "Number of executed statements.",
+		}, metricsLabels)
+
+		opts.Registerer.MustRegister(sink.statements)
+	}
+
+	if opts.Registerer != nil && sink.duration == nil {
+		sink.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "dbx_statement_duration_seconds",
+			Help:    "Duration of executed statements in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, metricsLabels)
+
+		opts.Registerer.MustRegister(sink.duration)
+	}
+
+	if sink.statements == nil && sink.duration == nil {
+		return nil
+	}
+
+	return sink
+}
+
+func (s *prometheusSink) ObserveStatement(ctx context.Context, operation, outcome string, duration time.Duration) {
+	name := OperationName(ctx)
+
+	if s.statements != nil {
+		s.statements.WithLabelValues(operation, outcome, name).Inc()
+	}
+
+	if s.duration != nil {
+		s.duration.WithLabelValues(operation, outcome, name).Observe(duration.Seconds())
+	}
+}
@@ -0,0 +1,116 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+type recordingSink struct {
+	observed []string
+}
+
+func (s *recordingSink) ObserveStatement(ctx context.Context, operation, outcome string, duration time.Duration) {
+	s.observed = append(s.observed, operation+":"+outcome)
+}
+
+func TestWithMetrics(test *testing.T) {
+	test.Run("should observe operation and outcome of statements", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectQuery("SELECT name").WillReturnError(testErr)
+		dmock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		sink := &recordingSink{}
+		exec := dbx.WithMetrics(db, dbx.MetricsOptions{Sink: sink})
+
+		_, err := exec.Exec("UPDATE users SET active = true")
+		assert.NoError(t, err)
+
+		_, err = exec.QueryContext(context.Background(), "SELECT name FROM users")
+		assert.Equal(t, testErr, err)
+
+		var count int
+		assert.NoError(t, exec.QueryRow("SELECT count(*) FROM users").Scan(&count))
+
+		assert.Equal(t, []string{"exec:ok", "query:error", "queryrow:ok"}, sink.observed)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should ignore nil sink", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		exec := dbx.WithMetrics(db, dbx.MetricsOptions{})
+
+		_, err := exec.Exec("UPDATE users SET active = true")
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should register prometheus collectors", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		registry := prometheus.NewRegistry()
+		exec := dbx.WithMetrics(db, dbx.MetricsOptions{Registerer: registry})
+
+		for i := 0; i < 2; i++ {
+			_, err := exec.Exec("UPDATE users SET active = true")
+			assert.NoError(t, err)
+		}
+
+		families, err := registry.Gather()
+		assert.NoError(t, err)
+
+		names := make([]string, 0, len(families))
+
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+
+		assert.ElementsMatch(t, []string{"dbx_statements_total", "dbx_statement_duration_seconds"}, names)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should use pre-built prometheus collectors", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectQuery("SELECT name").WillReturnError(testErr)
+
+		statements := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "app_statements_total"}, []string{"operation", "outcome", "name"})
+		exec := dbx.WithMetrics(db, dbx.MetricsOptions{Statements: statements})
+
+		_, err := exec.Exec("UPDATE users SET active = true")
+		assert.NoError(t, err)
+
+		_, err = exec.Query("SELECT name FROM users")
+		assert.Equal(t, testErr, err)
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(statements.WithLabelValues("exec", "ok", "")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(statements.WithLabelValues("query", "error", "")))
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,141 @@
+package dbx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NamedExec executes a given statement with :name parameters resolved against a given struct or map.
+// Struct fields are matched the same way as by QueryAll, while map keys are matched exactly.
+// Parameters are rewritten to ? placeholders, so the driver must support them.
+// Parameters within quoted strings and comments and PostgreSQL :: casts are left intact.
+func NamedExec(ctx Context, query string, arg interface{}) (sql.Result, error) {
+	bound, args, err := bindNamed(query, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.Executor().ExecContext(ctx, bound, args...)
+}
+
+// NamedQuery executes a given query with :name parameters resolved against a given struct or map.
+// Parameters are resolved the same way as by NamedExec.
+func NamedQuery(ctx Context, query string, arg interface{}) (*sql.Rows, error) {
+	bound, args, err := bindNamed(query, arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.Executor().QueryContext(ctx, bound, args...)
+}
+
+// bindNamed rewrites :name parameters of a given query to ? placeholders and returns the arguments in their order.
+func bindNamed(query string, arg interface{}) (string, []interface{}, error) {
+	lookup, err := namedLookup(arg)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		end := i + 1
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end = skipQuoted(query, i)
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end = skipLineComment(query, i)
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end = skipBlockComment(query, i)
+		case c == '$':
+			end = skipDollarQuoted(query, i)
+		case c == ':' && strings.HasPrefix(query[i:], "::"):
+			end = i + 2
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			for end < len(query) && (isNameStart(query[end]) || isDigit(query[end])) {
+				end++
+			}
+
+			name := query[i+1 : end]
+			value, found := lookup(name)
+
+			if !found {
+				return "", nil, fmt.Errorf("%w: %s", ErrUnknownParameter, name)
+			}
+
+			sb.WriteByte('?')
+			args = append(args, value)
+			i = end
+
+			continue
+		}
+
+		sb.WriteString(query[i:end])
+		i = end
+	}
+
+	return sb.String(), args, nil
+}
+
+// namedLookup returns a function that resolves parameter values from a given struct or map with string keys.
+func namedLookup(arg interface{}) (func(name string) (interface{}, bool), error) {
+	value := reflect.ValueOf(arg)
+
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String:
+		return func(name string) (interface{}, bool) {
+			found := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+
+			if !found.IsValid() {
+				return nil, false
+			}
+
+			return found.Interface(), true
+		}, nil
+	case value.Kind() == reflect.Struct:
+		fields := structFields(value.Type())
+
+		return func(name string) (interface{}, bool) {
+			path, found := fields[strings.ToLower(name)]
+
+			if !found {
+				return nil, false
+			}
+
+			field := value
+
+			for i, index := range path {
+				if i > 0 && field.Kind() == reflect.Pointer {
+					// a field of a nil embedded struct
+					if field.IsNil() {
+						return nil, true
+					}
+
+					field = field.Elem()
+				}
+
+				field = field.Field(index)
+			}
+
+			return field.Interface(), true
+		}, nil
+	default:
+		return nil, fmt.Errorf("named parameters require a struct or a map with string keys, got %T", arg)
+	}
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,78 @@
+package dbx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestNamedExec(test *testing.T) {
+	test.Run("should bind struct fields", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE accounts SET login = ?, created_at = ?::date WHERE id = ? AND note <> ':id'").
+			WithArgs("john", "2023-01-01", int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		acc := account{ID: 1, UserName: "john", Timestamps: &Timestamps{CreatedAt: "2023-01-01"}}
+
+		_, err := dbx.NamedExec(db.Context(context.Background()),
+			"UPDATE accounts SET login = :login, created_at = :created_at::date WHERE id = :id AND note <> ':id'", &acc)
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should bind map values", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("DELETE FROM accounts WHERE id = ? OR parent_id = ?").
+			WithArgs(7, 7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		_, err := dbx.NamedExec(db.Context(context.Background()),
+			"DELETE FROM accounts WHERE id = :id OR parent_id = :id", map[string]interface{}{"id": 7})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should fail on unknown parameter", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		_, err := dbx.NamedExec(db.Context(context.Background()),
+			"DELETE FROM accounts WHERE id = :uid", map[string]interface{}{"id": 7})
+
+		assert.ErrorIs(t, err, dbx.ErrUnknownParameter)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestNamedQuery(test *testing.T) {
+	test.Run("should bind parameters", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT id FROM accounts WHERE login = ?").
+			WithArgs("jane").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		rows, err := dbx.NamedQuery(db.Context(context.Background()),
+			"SELECT id FROM accounts WHERE login = :login", account{UserName: "jane"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, rows.Close())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,22 @@
+package dbx
+
+import "context"
+
+// operationName is the name of the operation set via Named.
+type operationName string
+
+// Named returns a copy of a given context that names the operation performed with it, e.g. "GetUser".
+// The name is a stable, low cardinality label of the statements executed with the returned context,
+// reported by WithMetrics and WithTracing. It is preserved by transactions created from the returned context.
+// Non-context executor methods are not named, since they are not passed the context.
+func Named(ctx Context, name string) Context {
+	return SetValue(ctx, operationName(name))
+}
+
+// OperationName returns the name of the operation set via Named, or an empty string if the operation is not named,
+// e.g. to label the measurements of a custom MetricsSink.
+func OperationName(ctx context.Context) string {
+	name, _ := GetValue[operationName](ctx)
+
+	return string(name)
+}
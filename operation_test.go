@@ -0,0 +1,75 @@
+package dbx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type namingSink struct {
+	names []string
+}
+
+func (s *namingSink) ObserveStatement(ctx context.Context, operation, outcome string, duration time.Duration) {
+	s.names = append(s.names, dbx.OperationName(ctx))
+}
+
+func TestNamed(test *testing.T) {
+	test.Run("should label metrics of named operations", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		statements := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "app_statements_total"}, []string{"operation", "outcome", "name"})
+		ctx := dbx.NewContext(context.Background(), dbx.WithMetrics(db, dbx.MetricsOptions{Statements: statements}))
+
+		var name string
+		named := dbx.Named(ctx, "GetUser")
+		assert.NoError(t, named.Executor().QueryRowContext(named, "SELECT name FROM users WHERE id = 1").Scan(&name))
+
+		_, err := ctx.Executor().ExecContext(ctx, "UPDATE users SET active = true")
+		assert.NoError(t, err)
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(statements.WithLabelValues("queryrow", "ok", "GetUser")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(statements.WithLabelValues("exec", "ok", "")))
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should name statements of transactions", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectCommit()
+
+		sink := &namingSink{}
+		tracer := &recordingTracer{Tracer: trace.NewNoopTracerProvider().Tracer("test")}
+		exec := dbx.WithTracing(dbx.WithMetrics(db, dbx.MetricsOptions{Sink: sink}), tracer)
+		ctx := dbx.Named(dbx.NewContext(context.Background(), exec), "ActivateUser")
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			_, err := c.Executor().ExecContext(c, "UPDATE users SET active = true")
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ActivateUser"}, sink.names)
+		assert.Len(t, tracer.spans, 1)
+		assert.Contains(t, tracer.spans[0].attrs, attribute.String("db.operation", "ActivateUser"))
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
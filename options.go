@@ -1,32 +1,144 @@
 package dbx
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
 
 type (
 	options struct {
 		*sql.TxOptions
-		AlwaysCreate bool
+		AlwaysCreate          bool
+		AlwaysReuse           bool
+		CommitDespiteDeadline bool
+		Manager               TransactionManager
+		SingleFlightKey       func(ctx context.Context) string
+		CommitGuard           CommitGuard
+		IsolationFromContext  func(ctx context.Context) (sql.IsolationLevel, bool)
+		MaxStatements         int
+		ManualCleanup         func(tx Transactor)
+		MaxAttempts           int
+		RetryPolicy           func(err error) bool
+		Backoff               func(retry int) time.Duration
+		Savepoint             bool
+		Timeout               time.Duration
+		DeadlineFraction      float64
+		RollbackEmpty         bool
+		OnCommit              []func()
+		OnRollback            []func(err error)
+		explicitIsolation     bool
+		summary               *summaryRecorder
 	}
 
 	Option func(opts *options)
 )
 
-func newOptions(setters []Option) *options {
+func newOptions(setters []Option) (*options, error) {
 	opts := &options{
 		TxOptions: &sql.TxOptions{},
+		Manager:   DefaultTransactionManager(),
+		RetryPolicy: func(err error) bool {
+			return false
+		},
 	}
 
 	for _, setter := range setters {
 		setter(opts)
 	}
 
-	return opts
+	if opts.AlwaysCreate && opts.AlwaysReuse {
+		return nil, fmt.Errorf("%w: a transaction cannot be both new and reused", ErrConflictingOptions)
+	}
+
+	return opts, nil
+}
+
+// txOptions returns transaction options for a given context.
+func (opts *options) txOptions(ctx context.Context) *sql.TxOptions {
+	if opts.explicitIsolation || opts.IsolationFromContext == nil {
+		return opts.TxOptions
+	}
+
+	level, ok := opts.IsolationFromContext(ctx)
+
+	if !ok {
+		return opts.TxOptions
+	}
+
+	return &sql.TxOptions{
+		Isolation: level,
+		ReadOnly:  opts.ReadOnly,
+	}
+}
+
+// deadline returns the deadline of a new transaction set via WithTimeout,
+// unless a given context has an earlier one.
+func (opts *options) deadline(ctx context.Context) (time.Time, bool) {
+	if opts.Timeout <= 0 {
+		return time.Time{}, false
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+
+	if current, ok := ctx.Deadline(); ok && !deadline.Before(current) {
+		return time.Time{}, false
+	}
+
+	return deadline, true
+}
+
+// operationDeadline returns the deadline of the operation set via WithDeadlineFraction,
+// given the context the transaction is bound to.
+func (opts *options) operationDeadline(ctx context.Context) (time.Time, bool) {
+	current, ok := ctx.Deadline()
+
+	if !ok || opts.DeadlineFraction <= 0 || opts.DeadlineFraction >= 1 {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+
+	return now.Add(time.Duration(float64(current.Sub(now)) * opts.DeadlineFraction)), true
+}
+
+// committed calls the callbacks set via WithOnCommit.
+func (opts *options) committed() {
+	for _, fn := range opts.OnCommit {
+		fn()
+	}
+}
+
+// rolledBack calls the callbacks set via WithOnRollback.
+func (opts *options) rolledBack(err error) {
+	for _, fn := range opts.OnRollback {
+		fn(err)
+	}
 }
 
 // WithIsolationLevel sets the isolation level for the transaction.
 func WithIsolationLevel(level sql.IsolationLevel) Option {
 	return func(opts *options) {
 		opts.Isolation = level
+		opts.explicitIsolation = true
+	}
+}
+
+// WithTxOptions sets the isolation level and the read-only flag for the transaction at once.
+// Options are applied in order, so WithIsolationLevel and WithReadOnly override the respective fields if they follow it
+// and are overridden by it otherwise. A given value is copied, so it is not modified by the following options.
+func WithTxOptions(txOpts *sql.TxOptions) Option {
+	return func(opts *options) {
+		cp := sql.TxOptions{}
+
+		if txOpts != nil {
+			cp = *txOpts
+		}
+
+		opts.TxOptions = &cp
+		opts.explicitIsolation = cp.Isolation != sql.LevelDefault
 	}
 }
 
@@ -43,3 +155,183 @@ func WithNewTransaction() Option {
 		opts.AlwaysCreate = true
 	}
 }
+
+// WithIndependentTransaction is an alias for WithNewTransaction.
+// It creates a new transaction that is independent of any existing transaction in the context.
+func WithIndependentTransaction() Option {
+	return WithNewTransaction()
+}
+
+// WithReuseTransaction explicitly reuses an existing transaction in the context, which is the default behavior.
+// It conflicts with WithNewTransaction and WithIndependentTransaction.
+func WithReuseTransaction() Option {
+	return func(opts *options) {
+		opts.AlwaysReuse = true
+	}
+}
+
+// WithCommitDespiteDeadline attempts to commit the transaction even if its context is done by the time the operation returns.
+// By default, such a transaction is rolled back and the context error is returned.
+// Since database/sql rolls back transactions whose context is done on its own,
+// the transaction is bound to a context that is not done by the deadline or cancellation of the given one,
+// but only by Context.Cancel. Statements executed by the operation keep honouring the deadline.
+func WithCommitDespiteDeadline() Option {
+	return func(opts *options) {
+		opts.CommitDespiteDeadline = true
+	}
+}
+
+// WithTransactionManager sets a custom manager that handles begin, commit and rollback of new transactions.
+func WithTransactionManager(manager TransactionManager) Option {
+	return func(opts *options) {
+		opts.Manager = manager
+	}
+}
+
+// WithSingleFlight coalesces concurrent transactions with the same key, returned by a given function.
+// Only the first transaction is performed, while the others wait for it to finish and receive its result and error.
+// An empty key disables coalescing for the call.
+// Since the coalesced calls share the first call's transaction, it is intended for idempotent top-level transactions.
+// Keys are shared by the whole process, so calls on different databases with the same key are coalesced as well.
+// A waiting call returns once its own context is done, while the first call keeps running.
+func WithSingleFlight(key func(ctx context.Context) string) Option {
+	return func(opts *options) {
+		opts.SingleFlightKey = key
+	}
+}
+
+// WithCommitGuard sets how the transaction handles Commit and Rollback calls made by the operation,
+// e.g. by type asserting the context executor to Transactor.
+// Only the owner of the transaction commits or rolls it back, so by default such calls are no-ops.
+func WithCommitGuard(mode CommitGuard) Option {
+	return func(opts *options) {
+		opts.CommitGuard = mode
+	}
+}
+
+// WithIsolationFromContext sets a function that resolves the isolation level from the context when a new transaction begins,
+// e.g. to apply serializable isolation to every transaction of a particular tenant.
+// If the function returns false, the default isolation level is used.
+// An isolation level set via WithIsolationLevel takes precedence.
+func WithIsolationFromContext(resolve func(ctx context.Context) (sql.IsolationLevel, bool)) Option {
+	return func(opts *options) {
+		opts.IsolationFromContext = resolve
+	}
+}
+
+// WithMaxStatements limits the number of statements the operation may execute.
+// Statements beyond the limit fail with ErrStatementLimitExceeded without reaching the database.
+func WithMaxStatements(n int) Option {
+	return func(opts *options) {
+		opts.MaxStatements = n
+	}
+}
+
+// WithManualCleanup disables the automatic rollback of a new transaction when the operation fails.
+// Instead, the open transaction is passed to a given function, so that its state can be inspected, e.g. while debugging a test.
+// It is intended for diagnostics only: the caller must commit or roll back the transaction,
+// otherwise its connection leaks until the parent context is done.
+func WithManualCleanup(fn func(tx Transactor)) Option {
+	return func(opts *options) {
+		opts.ManualCleanup = fn
+	}
+}
+
+// WithRetry re-runs the operation within a new transaction up to a given number of attempts in total
+// when the operation or the commit fails with an error considered retriable by the policy set via WithRetryPolicy,
+// e.g. due to a serialization failure or a deadlock.
+// Only transactions created by the call are retried, operations performed within a reused transaction fail right away.
+// Nothing is retried once the context is done. If all attempts fail, the last error is returned.
+// Since the operation may run more than once, it must not have side effects outside the transaction.
+func WithRetry(maxAttempts int) Option {
+	return func(opts *options) {
+		opts.MaxAttempts = maxAttempts
+	}
+}
+
+// WithRetryPolicy sets a function that decides whether a transaction failed with a given error is worth retrying,
+// e.g. by checking driver specific error codes for serialization failures and deadlocks.
+// It is consulted for operation and commit errors. By default, no errors are retried.
+// The same classifier can be passed to RetryExec and RetryQuery, which default to IsTransient.
+func WithRetryPolicy(retriable func(err error) bool) Option {
+	return func(opts *options) {
+		opts.RetryPolicy = retriable
+	}
+}
+
+// WithBackoff sets a delay between transaction attempts made due to WithRetry.
+// The delay before n-th retry, starting from zero, is base * factor^n capped at max.
+// If the context is done while waiting, no more attempts are made and the context error is returned.
+func WithBackoff(base time.Duration, factor float64, max time.Duration) Option {
+	return func(opts *options) {
+		opts.Backoff = func(retry int) time.Duration {
+			delay := float64(base) * math.Pow(factor, float64(retry))
+
+			if delay > float64(max) {
+				return max
+			}
+
+			return time.Duration(delay)
+		}
+	}
+}
+
+// WithSavepoint runs the operation within a savepoint when an existing transaction is reused,
+// so that an operation error rolls back only the changes made by the operation instead of the whole transaction.
+// The savepoint is released once the operation succeeds. A new transaction is created as usual if there is no existing one.
+func WithSavepoint() Option {
+	return func(opts *options) {
+		opts.Savepoint = true
+	}
+}
+
+// WithTimeout limits the time a new transaction may take, including the operation.
+// The effective deadline is the earlier of the timeout and the deadline of the parent context,
+// so the timeout never extends the deadline set by the caller.
+// If the deadline passes, the transaction is rolled back and context.DeadlineExceeded is returned.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.Timeout = timeout
+	}
+}
+
+// WithOnCommit adds a callback that is called after a new transaction is committed, e.g. to invalidate a cache.
+// It is not called for operations performed within a reused transaction.
+// Callbacks are called in the order they are added.
+func WithOnCommit(fn func()) Option {
+	return func(opts *options) {
+		opts.OnCommit = append(opts.OnCommit, fn)
+	}
+}
+
+// WithOnRollback adds a callback that is called with the error returned by the transaction after a new transaction is rolled back.
+// It is not called for operations performed within a reused transaction.
+// Callbacks are called in the order they are added.
+func WithOnRollback(fn func(err error)) Option {
+	return func(opts *options) {
+		opts.OnRollback = append(opts.OnRollback, fn)
+	}
+}
+
+// WithDeadlineFraction limits the time the operation of a new transaction may take to a given fraction of the time remaining until
+// the deadline of the transaction, e.g. 0.8, leaving the rest for the commit or rollback.
+// The deadline of the transaction is the earlier of the parent context deadline and the timeout set via WithTimeout.
+// The fraction applies to the context passed to the operation only, while the transaction itself is bound to its own deadline,
+// so the commit may run after the operation deadline as long as the transaction deadline has not passed.
+// If the operation returns after its deadline, the transaction is rolled back and context.DeadlineExceeded is returned.
+// It takes effect only if the transaction has a deadline and the fraction is between 0 and 1 exclusively.
+func WithDeadlineFraction(f float64) Option {
+	return func(opts *options) {
+		opts.DeadlineFraction = f
+	}
+}
+
+// WithRollbackEmpty rolls back a new transaction instead of committing it if the operation succeeds without executing any statements,
+// e.g. when a guard short-circuits it. Such a transaction is still considered committed:
+// commit callbacks are called and the summary outcome is OutcomeCommitted.
+// By default, empty transactions are committed.
+func WithRollbackEmpty() Option {
+	return func(opts *options) {
+		opts.RollbackEmpty = true
+	}
+}
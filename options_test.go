@@ -0,0 +1,956 @@
+package dbx_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+// capturingDatabase records transaction options passed to BeginTx.
+type capturingDatabase struct {
+	dbx.Database
+	captured []*sql.TxOptions
+}
+
+func (d *capturingDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	d.captured = append(d.captured, opts)
+
+	return d.Database.BeginTx(ctx, opts)
+}
+
+type strictKey struct{}
+
+func TestWithIsolationFromContext(test *testing.T) {
+	isolation := dbx.WithIsolationFromContext(func(ctx context.Context) (sql.IsolationLevel, bool) {
+		strict, _ := ctx.Value(strictKey{}).(bool)
+
+		return sql.LevelSerializable, strict
+	})
+
+	test.Run("should resolve isolation level from context", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := &capturingDatabase{Database: dbx.New(dbMock)}
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		op := func(c dbx.Context) error {
+			return nil
+		}
+
+		strict := context.WithValue(context.Background(), strictKey{}, true)
+
+		assert.NoError(t, dbx.Transaction(strict, db, op, isolation))
+		assert.NoError(t, dbx.Transaction(context.Background(), db, op, isolation))
+
+		assert.Equal(t, sql.LevelSerializable, db.captured[0].Isolation)
+		assert.Equal(t, sql.LevelDefault, db.captured[1].Isolation)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should prefer explicit isolation level", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := &capturingDatabase{Database: dbx.New(dbMock)}
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		strict := context.WithValue(context.Background(), strictKey{}, true)
+
+		err := dbx.Transaction(strict, db, func(c dbx.Context) error {
+			return nil
+		}, isolation, dbx.WithIsolationLevel(sql.LevelReadCommitted))
+
+		assert.NoError(t, err)
+		assert.Equal(t, sql.LevelReadCommitted, db.captured[0].Isolation)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithMaxStatements(test *testing.T) {
+	test.Run("should reject statements beyond the limit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SELECT 2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			for _, query := range []string{"SELECT 1", "SELECT 2", "SELECT 3"} {
+				if _, e := c.Executor().Exec(query); e != nil {
+					return e
+				}
+			}
+
+			return nil
+		}, dbx.WithMaxStatements(2))
+
+		assert.ErrorIs(t, err, dbx.ErrStatementLimitExceeded)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should reject rows queries beyond the limit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			var n int
+
+			if e := c.Executor().QueryRow("SELECT 1").Scan(&n); e != nil {
+				return e
+			}
+
+			return c.Executor().QueryRow("SELECT 2").Scan(&n)
+		}, dbx.WithMaxStatements(1))
+
+		assert.ErrorIs(t, err, dbx.ErrStatementLimitExceeded)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithManualCleanup(test *testing.T) {
+	test.Run("should hand failed transaction over instead of rolling it back", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		dmock.ExpectRollback()
+
+		var open dbx.Transactor
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			c.Executor().Exec("INSERT INTO users")
+
+			return testErr
+		}, dbx.WithManualCleanup(func(tx dbx.Transactor) {
+			open = tx
+		}))
+
+		assert.Equal(t, testErr, err)
+		assert.NotNil(t, open)
+
+		var count int
+		assert.NoError(t, open.QueryRow("SELECT count(*) FROM users").Scan(&count))
+		assert.Equal(t, 1, count)
+
+		assert.NoError(t, open.Rollback())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithRetry(test *testing.T) {
+	retryAll := dbx.WithRetryPolicy(func(err error) bool {
+		return true
+	})
+
+	test.Run("should retry failed operation within a new transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("serialization failure")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			if attempts == 1 {
+				return testErr
+			}
+
+			return nil
+		}, dbx.WithRetry(3), retryAll)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should retry failed commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("deadlock detected")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit().WillReturnError(testErr)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			return nil
+		}, dbx.WithRetry(3), retryAll)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return last error when all attempts fail", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		for i := 0; i < 3; i++ {
+			dmock.ExpectBegin()
+			dmock.ExpectRollback()
+		}
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			return errors.New("attempt failed")
+		}, dbx.WithRetry(3), retryAll)
+
+		assert.EqualError(t, err, "attempt failed")
+		assert.Equal(t, 3, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not retry reused transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				attempts++
+
+				return testErr
+			}, dbx.WithRetry(3), retryAll)
+		})
+
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, 1, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithRetryPolicy(test *testing.T) {
+	retriableErr := errors.New("serialization failure")
+	policy := dbx.WithRetryPolicy(func(err error) bool {
+		return errors.Is(err, retriableErr)
+	})
+
+	test.Run("should not retry by default", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			return retriableErr
+		}, dbx.WithRetry(3))
+
+		assert.Equal(t, retriableErr, err)
+		assert.Equal(t, 1, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should retry only errors accepted by policy", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		otherErr := errors.New("constraint violation")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			if attempts == 1 {
+				return retriableErr
+			}
+
+			return otherErr
+		}, dbx.WithRetry(3), policy)
+
+		assert.Equal(t, otherErr, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should consult policy for commit errors", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit().WillReturnError(retriableErr)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		attempts := 0
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			return nil
+		}, dbx.WithRetry(3), policy)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithBackoff(test *testing.T) {
+	retryAll := dbx.WithRetryPolicy(func(err error) bool {
+		return true
+	})
+
+	test.Run("should wait between attempts", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		for i := 0; i < 3; i++ {
+			dmock.ExpectBegin()
+			dmock.ExpectRollback()
+		}
+
+		var attempts []time.Time
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			attempts = append(attempts, time.Now())
+
+			return errors.New("attempt failed")
+		}, dbx.WithRetry(3), retryAll, dbx.WithBackoff(20*time.Millisecond, 2, 30*time.Millisecond))
+
+		assert.Error(t, err)
+		assert.Len(t, attempts, 3)
+		assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), 20*time.Millisecond)
+		assert.GreaterOrEqual(t, attempts[2].Sub(attempts[1]), 30*time.Millisecond)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should abort when context is done while waiting", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		attempts := 0
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			attempts++
+
+			return errors.New("attempt failed")
+		}, dbx.WithRetry(3), retryAll, dbx.WithBackoff(time.Minute, 2, time.Hour))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, attempts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithSavepoint(test *testing.T) {
+	test.Run("should roll back nested operation to savepoint", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("INSERT INTO logs").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectExec("ROLLBACK TO SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			if _, err := c.Executor().Exec("INSERT INTO users"); err != nil {
+				return err
+			}
+
+			nestedErr := dbx.Transaction(c, db, func(c dbx.Context) error {
+				c.Executor().Exec("INSERT INTO logs")
+
+				return testErr
+			}, dbx.WithSavepoint())
+
+			assert.Equal(t, testErr, nestedErr)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should name savepoints after nesting depth", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SAVEPOINT dbx_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		noop := func(c dbx.Context) error {
+			return nil
+		}
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			err := dbx.Transaction(c, db, func(c dbx.Context) error {
+				return dbx.Transaction(c, db, noop, dbx.WithSavepoint())
+			}, dbx.WithSavepoint())
+
+			if err != nil {
+				return err
+			}
+
+			return dbx.Transaction(c, db, noop, dbx.WithSavepoint())
+		}, dbx.WithSavepoint())
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not count savepoint statements", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("INSERT INTO logs").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		summary, err := dbx.TransactionWithSummary(context.Background(), db, func(c dbx.Context) error {
+			if _, err := c.Executor().Exec("INSERT INTO users"); err != nil {
+				return err
+			}
+
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				_, err := c.Executor().Exec("INSERT INTO logs")
+
+				return err
+			}, dbx.WithSavepoint())
+		}, dbx.WithMaxStatements(2))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), summary.Statements)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return release error", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnError(testErr)
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				return nil
+			}, dbx.WithSavepoint())
+		})
+
+		assert.ErrorIs(t, err, dbx.ErrReleaseSavepoint)
+		assert.ErrorIs(t, err, testErr)
+		assert.NotErrorIs(t, err, dbx.ErrCommit)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestSavepointDepth(test *testing.T) {
+	test.Run("should track nested savepoints", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SAVEPOINT dbx_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("ROLLBACK TO SAVEPOINT dbx_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("RELEASE SAVEPOINT dbx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			assert.Equal(t, 0, dbx.SavepointDepth(c))
+
+			err := dbx.Transaction(c, db, func(c dbx.Context) error {
+				assert.Equal(t, 1, dbx.SavepointDepth(c))
+
+				err := dbx.Transaction(c, db, func(c dbx.Context) error {
+					assert.Equal(t, 2, dbx.SavepointDepth(c))
+					assert.Equal(t, []string{"dbx_1", "dbx_2"}, dbx.SavepointNames(c))
+
+					return testErr
+				}, dbx.WithSavepoint())
+
+				assert.Equal(t, testErr, err)
+				assert.Equal(t, 1, dbx.SavepointDepth(c))
+				assert.Equal(t, []string{"dbx_1"}, dbx.SavepointNames(c))
+
+				return nil
+			}, dbx.WithSavepoint())
+
+			assert.Equal(t, 0, dbx.SavepointDepth(c))
+			assert.Empty(t, dbx.SavepointNames(c))
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithCommitDespiteDeadline(test *testing.T) {
+	test.Run("should commit after deadline", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			<-c.Done()
+
+			return nil
+		}, dbx.WithCommitDespiteDeadline())
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should rollback when cancelled", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			c.Cancel(testErr)
+
+			return nil
+		}, dbx.WithCommitDespiteDeadline())
+
+		assert.Equal(t, testErr, err)
+		assert.Eventually(t, func() bool {
+			return dmock.ExpectationsWereMet() == nil
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestWithTimeout(test *testing.T) {
+	test.Run("should apply timeout shorter than context deadline", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+
+		started := time.Now()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			deadline, ok := c.Deadline()
+
+			assert.True(t, ok)
+			assert.WithinDuration(t, started.Add(time.Minute), deadline, time.Second)
+
+			return nil
+		}, dbx.WithTimeout(time.Minute))
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should keep context deadline shorter than timeout", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		expected, _ := ctx.Deadline()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			deadline, ok := c.Deadline()
+
+			assert.True(t, ok)
+			assert.Equal(t, expected, deadline)
+
+			return nil
+		}, dbx.WithTimeout(time.Hour))
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should rollback when timeout passes", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			<-c.Done()
+
+			return nil
+		}, dbx.WithTimeout(10*time.Millisecond))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWithTxOptions(test *testing.T) {
+	test.Run("should apply options in order", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := &capturingDatabase{Database: dbx.New(dbMock)}
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		op := func(c dbx.Context) error {
+			return nil
+		}
+
+		txOpts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+		assert.NoError(t, dbx.Transaction(context.Background(), db, op,
+			dbx.WithTxOptions(txOpts),
+			dbx.WithIsolationLevel(sql.LevelSerializable),
+		))
+		assert.NoError(t, dbx.Transaction(context.Background(), db, op,
+			dbx.WithReadOnly(false),
+			dbx.WithTxOptions(txOpts),
+		))
+
+		assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}, db.captured[0])
+		assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, db.captured[1])
+		assert.Equal(t, sql.LevelRepeatableRead, txOpts.Isolation)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithOnCommit(test *testing.T) {
+	test.Run("should call callbacks in order after commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		var calls []string
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				return nil
+			}, dbx.WithOnCommit(func() {
+				calls = append(calls, "reused")
+			}))
+		}, dbx.WithOnCommit(func() {
+			calls = append(calls, "first")
+		}), dbx.WithOnCommit(func() {
+			calls = append(calls, "second")
+		}), dbx.WithOnRollback(func(err error) {
+			calls = append(calls, "rollback")
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithOnRollback(test *testing.T) {
+	test.Run("should call callbacks with error after rollback", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		var calls []error
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return testErr
+		}, dbx.WithOnRollback(func(err error) {
+			calls = append(calls, err)
+		}), dbx.WithOnRollback(func(err error) {
+			calls = append(calls, err)
+		}), dbx.WithOnCommit(func() {
+			t.Fatal("commit callback is called")
+		}))
+
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, []error{testErr, testErr}, calls)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+// slowCommitManager delays commits, e.g. to simulate replication pressure.
+type slowCommitManager struct {
+	dbx.TransactionManager
+	delay time.Duration
+}
+
+func (m slowCommitManager) Commit(ctx context.Context, tx dbx.Transactor) error {
+	time.Sleep(m.delay)
+
+	return m.TransactionManager.Commit(ctx, tx)
+}
+
+func TestWithDeadlineFraction(test *testing.T) {
+	test.Run("should leave remaining time for commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		var opCtx dbx.Context
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			opCtx = c
+			time.Sleep(100 * time.Millisecond)
+
+			return nil
+		},
+			dbx.WithDeadlineFraction(0.2),
+			dbx.WithTransactionManager(slowCommitManager{dbx.DefaultTransactionManager(), 200 * time.Millisecond}),
+		)
+
+		// the commit completes after the operation deadline, but before the parent one
+		assert.NoError(t, err)
+		assert.ErrorIs(t, opCtx.Err(), context.DeadlineExceeded)
+		assert.NoError(t, ctx.Err())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should rollback when operation exceeds its deadline", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			<-c.Done()
+
+			return nil
+		}, dbx.WithDeadlineFraction(0.1))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NoError(t, ctx.Err())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should derive deadline from remaining time", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		started := time.Now()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			deadline, ok := c.Deadline()
+
+			assert.True(t, ok)
+			assert.WithinDuration(t, started.Add(8*time.Second), deadline, 100*time.Millisecond)
+
+			return nil
+		}, dbx.WithDeadlineFraction(0.8))
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should apply fraction to timeout", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		started := time.Now()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			deadline, _ := c.Deadline()
+
+			assert.WithinDuration(t, started.Add(800*time.Millisecond), deadline, 100*time.Millisecond)
+
+			return nil
+		}, dbx.WithDeadlineFraction(0.8), dbx.WithTimeout(time.Second))
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not apply without deadline", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			_, ok := c.Deadline()
+
+			assert.False(t, ok)
+
+			return nil
+		}, dbx.WithDeadlineFraction(0.8))
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithRollbackEmpty(test *testing.T) {
+	test.Run("should rollback transaction without statements", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		committed := false
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return nil
+		}, dbx.WithRollbackEmpty(), dbx.WithOnCommit(func() {
+			committed = true
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, committed)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should commit transaction with statements", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return dbx.Transaction(c, db, func(c dbx.Context) error {
+				_, err := c.Executor().Exec("UPDATE users SET active = true")
+
+				return err
+			})
+		}, dbx.WithRollbackEmpty())
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,103 @@
+package dbx
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MultiRows wraps sql.Rows of a query that returns multiple result sets, e.g. a stored procedure call.
+// Support for multiple result sets depends on the driver: some drivers require it to be enabled explicitly
+// and some do not support it at all, in which case only the first result set is available.
+type MultiRows struct {
+	*sql.Rows
+}
+
+// SelectColumn executes a given query and scans the first column of each row into a slice.
+// Other columns are discarded.
+func SelectColumn[T any](ctx Context, query string, args ...interface{}) ([]T, error) {
+	rows, err := ctx.Executor().QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, ErrNoColumns
+	}
+
+	dest := make([]interface{}, len(columns))
+
+	for i := 1; i < len(dest); i++ {
+		dest[i] = new(interface{})
+	}
+
+	out := make([]T, 0)
+
+	for rows.Next() {
+		var value T
+		dest[0] = &value
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		out = append(out, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// QueryMulti executes a given query that returns multiple result sets.
+// The returned rows must be closed by the caller.
+func QueryMulti(ctx Context, query string, args ...interface{}) (*MultiRows, error) {
+	rows, err := ctx.Executor().QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiRows{rows}, nil
+}
+
+// ScanSet calls a given function for each row of the current result set.
+func (r *MultiRows) ScanSet(fn func(rows *sql.Rows) error) error {
+	for r.Next() {
+		if err := fn(r.Rows); err != nil {
+			return err
+		}
+	}
+
+	return r.Err()
+}
+
+// ScanSets scans consecutive result sets, calling the n-th function for each row of the n-th result set.
+// It returns ErrNoResultSet if the query returns fewer result sets than functions given.
+func (r *MultiRows) ScanSets(fns ...func(rows *sql.Rows) error) error {
+	for i, fn := range fns {
+		if i > 0 && !r.NextResultSet() {
+			if err := r.Err(); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("%w: %d", ErrNoResultSet, i)
+		}
+
+		if err := r.ScanSet(fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
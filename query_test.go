@@ -0,0 +1,129 @@
+package dbx_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestSelectColumn(test *testing.T) {
+	test.Run("should scan int64 column", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT id FROM users").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+
+		ids, err := dbx.SelectColumn[int64](db.Context(context.Background()), "SELECT id FROM users")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, ids)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should scan first string column", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name, id FROM users").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "id"}).AddRow("John", 1).AddRow("Doe", 2))
+
+		names, err := dbx.SelectColumn[string](db.Context(context.Background()), "SELECT name, id FROM users")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"John", "Doe"}, names)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return empty slice when there are no rows", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		ids, err := dbx.SelectColumn[int64](db.Context(context.Background()), "SELECT id FROM users")
+
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	test.Run("should fail when query returns no columns", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("DELETE FROM users").WillReturnRows(sqlmock.NewRows([]string{}))
+
+		_, err := dbx.SelectColumn[int64](db.Context(context.Background()), "DELETE FROM users")
+
+		assert.ErrorIs(t, err, dbx.ErrNoColumns)
+	})
+}
+
+func TestQueryMulti(test *testing.T) {
+	test.Run("should scan multiple result sets", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("CALL get_user_with_orders").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("John"),
+			sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2),
+		)
+
+		rows, err := dbx.QueryMulti(db.Context(context.Background()), "CALL get_user_with_orders(?)", 1)
+		assert.NoError(t, err)
+		defer rows.Close()
+
+		var name string
+		var orders []int64
+
+		err = rows.ScanSets(func(rows *sql.Rows) error {
+			return rows.Scan(&name)
+		}, func(rows *sql.Rows) error {
+			var id int64
+
+			if e := rows.Scan(&id); e != nil {
+				return e
+			}
+
+			orders = append(orders, id)
+
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "John", name)
+		assert.Equal(t, []int64{1, 2}, orders)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should fail when result set is missing", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("CALL get_user_with_orders").WillReturnRows(
+			sqlmock.NewRows([]string{"name"}).AddRow("John"),
+		)
+
+		rows, err := dbx.QueryMulti(db.Context(context.Background()), "CALL get_user_with_orders(?)", 1)
+		assert.NoError(t, err)
+		defer rows.Close()
+
+		scan := func(rows *sql.Rows) error {
+			return nil
+		}
+
+		err = rows.ScanSets(scan, scan)
+
+		assert.ErrorIs(t, err, dbx.ErrNoResultSet)
+	})
+}
@@ -0,0 +1,31 @@
+package dbx
+
+import "database/sql"
+
+// Result wraps sql.Result with convenience methods.
+// It is returned by the exec helpers, while Executor keeps returning the standard sql.Result.
+type Result struct {
+	sql.Result
+}
+
+// Affected returns the number of rows affected by the statement.
+func (r Result) Affected() (int64, error) {
+	return r.RowsAffected()
+}
+
+// MustAffected returns the number of rows affected by the statement and panics if the driver fails to report it.
+// It is intended to be used in tests.
+func (r Result) MustAffected() int64 {
+	affected, err := r.RowsAffected()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return affected
+}
+
+// LastID returns the id generated by the database for the last inserted row.
+func (r Result) LastID() (int64, error) {
+	return r.LastInsertId()
+}
@@ -0,0 +1,40 @@
+package dbx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestResult(test *testing.T) {
+	test.Run("should return affected rows and last id", func(t *testing.T) {
+		res := dbx.Result{Result: sqlmock.NewResult(10, 2)}
+
+		affected, err := res.Affected()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), affected)
+		assert.Equal(t, int64(2), res.MustAffected())
+
+		id, err := res.LastID()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), id)
+	})
+
+	test.Run("should return driver errors", func(t *testing.T) {
+		testErr := errors.New("not supported")
+		res := dbx.Result{Result: sqlmock.NewErrorResult(testErr)}
+
+		_, err := res.Affected()
+		assert.Equal(t, testErr, err)
+
+		_, err = res.LastID()
+		assert.Equal(t, testErr, err)
+
+		assert.PanicsWithValue(t, testErr, func() {
+			res.MustAffected()
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package dbx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"time"
+)
+
+// RetryExec executes a given statement, retrying it up to a given number of attempts in total
+// while it fails with an error a given classifier considers retriable, waiting for a given duration between attempts.
+// A nil classifier defaults to IsTransient, which retries transient connection errors.
+// It is intended for idempotent statements executed outside of a transaction,
+// since a transaction does not survive the loss of its connection.
+func RetryExec(ctx Context, attempts int, backoff time.Duration, retriable func(err error) bool, query string, args ...interface{}) (Result, error) {
+	out, err := retryStatement(ctx, attempts, backoff, retriable, func() (sql.Result, error) {
+		return ctx.Executor().ExecContext(ctx, query, args...)
+	})
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{out}, nil
+}
+
+// RetryQuery executes a given query, retrying it up to a given number of attempts in total
+// while it fails with an error a given classifier considers retriable, waiting for a given duration between attempts.
+// A nil classifier defaults to IsTransient, which retries transient connection errors.
+// It is intended for reads executed outside of a transaction,
+// since a transaction does not survive the loss of its connection.
+func RetryQuery(ctx Context, attempts int, backoff time.Duration, retriable func(err error) bool, query string, args ...interface{}) (*sql.Rows, error) {
+	return retryStatement(ctx, attempts, backoff, retriable, func() (*sql.Rows, error) {
+		return ctx.Executor().QueryContext(ctx, query, args...)
+	})
+}
+
+func retryStatement[T any](ctx Context, attempts int, backoff time.Duration, retriable func(err error) bool, fn func() (T, error)) (T, error) {
+	if retriable == nil {
+		retriable = IsTransient
+	}
+
+	for attempt := 1; ; attempt++ {
+		out, err := fn()
+
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !retriable(err) {
+			return out, err
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return *new(T), ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// IsTransient reports whether a given error is caused by a broken connection or a network timeout.
+// It is the default classifier of RetryExec and RetryQuery and can be passed to WithRetryPolicy as well.
+func IsTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
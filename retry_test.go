@@ -0,0 +1,107 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryQuery(test *testing.T) {
+	test.Run("should retry query failed with connection error", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name FROM users").WillReturnError(timeoutError{})
+		dmock.ExpectQuery("SELECT name FROM users").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("John"))
+
+		rows, err := dbx.RetryQuery(db.Context(context.Background()), 3, time.Millisecond, nil, "SELECT name FROM users")
+
+		assert.NoError(t, err)
+
+		defer rows.Close()
+
+		var name string
+
+		assert.True(t, rows.Next())
+		assert.NoError(t, rows.Scan(&name))
+		assert.Equal(t, "John", name)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not retry other errors", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("syntax error")
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT name FROM users").WillReturnError(testErr)
+
+		_, err := dbx.RetryQuery(db.Context(context.Background()), 3, time.Millisecond, nil, "SELECT name FROM users")
+
+		assert.Equal(t, testErr, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestRetryExec(test *testing.T) {
+	test.Run("should retry errors of given classifier", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("serialization failure")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnError(testErr)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		retriable := func(err error) bool {
+			return errors.Is(err, testErr)
+		}
+
+		res, err := dbx.RetryExec(db.Context(context.Background()), 2, time.Millisecond, retriable, "UPDATE users SET active = true")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.MustAffected())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return result of successful attempt", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnError(timeoutError{})
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 3))
+
+		res, err := dbx.RetryExec(db.Context(context.Background()), 2, time.Millisecond, nil, "UPDATE users SET active = true")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), res.MustAffected())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return last error when all attempts fail", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnError(timeoutError{})
+		dmock.ExpectExec("UPDATE users").WillReturnError(timeoutError{})
+
+		_, err := dbx.RetryExec(db.Context(context.Background()), 2, time.Millisecond, nil, "UPDATE users SET active = true")
+
+		assert.Equal(t, timeoutError{}, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,170 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type (
+	// Resolver resolves a database for a given context, e.g. by a tenant id stored in the context.
+	Resolver func(ctx context.Context) (Database, error)
+
+	router struct {
+		resolve Resolver
+	}
+)
+
+// NewRouter returns a database that resolves an underlying database for every operation using a given resolver.
+// Contexts and transactions resolve the database once, when they are created, and stick to it.
+// Methods that do not accept a context resolve the database with context.Background().
+// Close and the pool setters are no-ops, since the underlying databases are owned by the resolver.
+func NewRouter(resolve Resolver) Database {
+	return &router{resolve}
+}
+
+func (r *router) Close() error {
+	return nil
+}
+
+func (r *router) DriverName() string {
+	db, err := r.resolve(context.Background())
+
+	if err != nil {
+		return ""
+	}
+
+	return db.DriverName()
+}
+
+func (r *router) Context(ctx context.Context) Context {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return NewContext(ctx, &errorExecutor{err})
+	}
+
+	return db.Context(ctx)
+}
+
+func (r *router) Begin() (*sql.Tx, error) {
+	return r.BeginTx(context.Background(), nil)
+}
+
+func (r *router) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db.BeginTx(ctx, opts)
+}
+
+func (r *router) acquireTransaction(ctx context.Context) (func(), error) {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if limiter, ok := db.(transactionLimiter); ok {
+		return limiter.acquireTransaction(ctx)
+	}
+
+	return func() {}, nil
+}
+
+func (r *router) Conn(ctx context.Context) (*sql.Conn, error) {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Conn(ctx)
+}
+
+func (r *router) Ping() error {
+	return r.PingContext(context.Background())
+}
+
+func (r *router) PingContext(ctx context.Context) error {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return db.PingContext(ctx)
+}
+
+// Stats returns the connection pool statistics of the database resolved with context.Background(),
+// or zero statistics if the resolution fails.
+func (r *router) Stats() sql.DBStats {
+	db, err := r.resolve(context.Background())
+
+	if err != nil {
+		return sql.DBStats{}
+	}
+
+	return db.Stats()
+}
+
+func (r *router) SetMaxOpenConns(n int) {}
+
+func (r *router) SetMaxIdleConns(n int) {}
+
+func (r *router) SetConnMaxLifetime(d time.Duration) {}
+
+func (r *router) WaitReady(ctx context.Context, interval time.Duration) error {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return db.WaitReady(ctx, interval)
+}
+
+func (r *router) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *router) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *router) QueryRow(query string, args ...interface{}) *sql.Row {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *router) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db.ExecContext(ctx, query, args...)
+}
+
+func (r *router) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return db.QueryContext(ctx, query, args...)
+}
+
+func (r *router) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	db, err := r.resolve(ctx)
+
+	if err != nil {
+		return newErrorRow(err)
+	}
+
+	return db.QueryRowContext(ctx, query, args...)
+}
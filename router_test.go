@@ -0,0 +1,80 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+type tenantKey struct{}
+
+func TestRouter(test *testing.T) {
+	test.Run("should route tenants to their databases", func(t *testing.T) {
+		dbMock1, dmock1, _ := sqlmock.New()
+		defer dbMock1.Close()
+
+		dbMock2, dmock2, _ := sqlmock.New()
+		defer dbMock2.Close()
+
+		tenants := map[string]dbx.Database{
+			"acme":   dbx.New(dbMock1),
+			"globex": dbx.New(dbMock2),
+		}
+
+		db := dbx.NewRouter(func(ctx context.Context) (dbx.Database, error) {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			found, ok := tenants[tenant]
+
+			if !ok {
+				return nil, errors.New("unknown tenant")
+			}
+
+			return found, nil
+		})
+
+		dmock1.ExpectBegin()
+		dmock1.ExpectExec("INSERT INTO acme").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock1.ExpectCommit()
+		dmock2.ExpectExec("INSERT INTO globex").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+		globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+
+		err := dbx.Transaction(acme, db, func(c dbx.Context) error {
+			_, e := c.Executor().Exec("INSERT INTO acme")
+
+			return e
+		})
+		assert.NoError(t, err)
+
+		_, err = db.Context(globex).Executor().Exec("INSERT INTO globex")
+		assert.NoError(t, err)
+
+		assert.NoError(t, dmock1.ExpectationsWereMet())
+		assert.NoError(t, dmock2.ExpectationsWereMet())
+	})
+
+	test.Run("should return resolution errors", func(t *testing.T) {
+		testErr := errors.New("unknown tenant")
+		db := dbx.NewRouter(func(ctx context.Context) (dbx.Database, error) {
+			return nil, testErr
+		})
+
+		ctx := context.Background()
+
+		_, err := db.Context(ctx).Executor().Exec("SELECT 1")
+		assert.Equal(t, testErr, err)
+
+		var out int
+		assert.Equal(t, testErr, db.QueryRowContext(ctx, "SELECT 1").Scan(&out))
+
+		err = dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			return nil
+		})
+		assert.Equal(t, testErr, err)
+	})
+}
@@ -0,0 +1,77 @@
+package dbx
+
+import "fmt"
+
+// savepointDepth is the number of savepoints a context is nested in.
+type savepointDepth int
+
+// savepointExecutor is the transaction savepoint statements are executed on.
+// It is not wrapped by the executors of the operations,
+// so that savepoint statements are neither limited nor counted as statements of the operations.
+type savepointExecutor struct {
+	Transactor
+}
+
+// savepointTransactor returns the transaction to execute savepoint statements on,
+// falling back to a given executor if the transaction was not created by dbx.
+func savepointTransactor(ctx Context, tx Transactor) Transactor {
+	if exec, ok := GetValue[savepointExecutor](ctx); ok {
+		return exec.Transactor
+	}
+
+	return tx
+}
+
+// SavepointDepth returns the number of savepoints created via WithSavepoint that a given context is nested in.
+// The depth of the context passed to the operation is restored once the nested operation releases or rolls back its savepoint.
+func SavepointDepth(ctx Context) int {
+	depth, _ := GetValue[savepointDepth](ctx)
+
+	return int(depth)
+}
+
+// SavepointNames returns the names of the savepoints that a given context is nested in, starting with the outermost one.
+func SavepointNames(ctx Context) []string {
+	depth := SavepointDepth(ctx)
+	names := make([]string, 0, depth)
+
+	for i := 1; i <= depth; i++ {
+		names = append(names, savepointName(savepointDepth(i)))
+	}
+
+	return names
+}
+
+// savepointName returns the name of a savepoint created at a given nesting depth.
+func savepointName(depth savepointDepth) string {
+	return fmt.Sprintf("dbx_%d", depth)
+}
+
+// beginSavepoint creates a savepoint within a given transaction, named after its nesting depth,
+// and returns a context that nested savepoints derive their names from.
+func beginSavepoint(ctx Context, tx Transactor) (Context, string, error) {
+	depth, _ := GetValue[savepointDepth](ctx)
+	depth++
+
+	name := savepointName(depth)
+
+	if _, err := savepointTransactor(ctx, tx).ExecContext(withInternal(ctx), "SAVEPOINT "+name); err != nil {
+		return nil, "", err
+	}
+
+	return SetValue(ctx, depth), name, nil
+}
+
+// releaseSavepoint releases a given savepoint, keeping its changes within the transaction.
+func releaseSavepoint(ctx Context, tx Transactor, name string) error {
+	_, err := savepointTransactor(ctx, tx).ExecContext(withInternal(ctx), "RELEASE SAVEPOINT "+name)
+
+	return err
+}
+
+// rollbackToSavepoint discards the changes made within the transaction since a given savepoint.
+func rollbackToSavepoint(ctx Context, tx Transactor, name string) error {
+	_, err := savepointTransactor(ctx, tx).ExecContext(withInternal(ctx), "ROLLBACK TO SAVEPOINT "+name)
+
+	return err
+}
@@ -0,0 +1,274 @@
+package dbx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// fieldsCache holds column to field mappings of struct types.
+var fieldsCache sync.Map
+
+// scanLocation is the location scanned times are converted to, set via WithScanLocation.
+type scanLocation struct {
+	loc *time.Location
+}
+
+// WithScanLocation returns a copy of a given context, so that QueryAll and QueryOne performed with it
+// convert times scanned into time.Time and *time.Time fields to a given location,
+// regardless of the location the driver returns them in.
+func WithScanLocation(ctx Context, loc *time.Location) Context {
+	return SetValue(ctx, scanLocation{loc})
+}
+
+// QueryAll executes a given query and scans each row into a struct of a given type.
+// Columns are mapped to fields by the db tag, falling back to the snake_case form of the field name.
+// Fields tagged with db:"-" are ignored and fields of embedded structs are mapped as if they were fields of the outer struct.
+// A column without a matching field results in ErrUnknownColumn.
+// Times are converted to the location set via WithScanLocation, if any.
+func QueryAll[T any](ctx Context, query string, args ...interface{}) ([]T, error) {
+	rows, err := ctx.Executor().QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	scan, err := newStructScanner[T](ctx, rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, 0)
+
+	for rows.Next() {
+		var value T
+
+		if err := scan(&value); err != nil {
+			return nil, err
+		}
+
+		out = append(out, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// newStructScanner returns a function that scans the current row into a struct of a given type.
+func newStructScanner[T any](ctx Context, rows *sql.Rows) (func(dest *T) error, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", typ)
+	}
+
+	columns, err := rows.Columns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := structFields(typ)
+	paths := make([][]int, len(columns))
+
+	for i, column := range columns {
+		path, found := fields[strings.ToLower(column)]
+
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, column)
+		}
+
+		paths[i] = path
+	}
+
+	targets := make([]interface{}, len(columns))
+	location, _ := GetValue[scanLocation](ctx)
+
+	return func(dest *T) error {
+		value := reflect.ValueOf(dest).Elem()
+
+		for i, path := range paths {
+			targets[i] = fieldByIndex(value, path).Addr().Interface()
+		}
+
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+
+		if location.loc != nil {
+			for _, target := range targets {
+				inLocation(target, location.loc)
+			}
+		}
+
+		return nil
+	}, nil
+}
+
+// inLocation converts the time a given scan target points to to a given location.
+func inLocation(target interface{}, loc *time.Location) {
+	switch t := target.(type) {
+	case *time.Time:
+		*t = t.In(loc)
+	case **time.Time:
+		if *t != nil {
+			converted := (*t).In(loc)
+			*t = &converted
+		}
+	}
+}
+
+// structFields returns the index paths of the fields of a given struct type keyed by lowercased column names.
+func structFields(typ reflect.Type) map[string][]int {
+	if cached, ok := fieldsCache.Load(typ); ok {
+		return cached.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	collectFields(typ, nil, map[reflect.Type]bool{}, fields)
+	fieldsCache.Store(typ, fields)
+
+	return fields
+}
+
+// collectFields collects the column paths of a given struct type.
+// Visited holds the struct types embedding the current one, so that self-referencing embedded pointers are not followed.
+func collectFields(typ reflect.Type, parent []int, visited map[reflect.Type]bool, fields map[string][]int) {
+	visited[typ] = true
+	defer delete(visited, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+
+		if tag == "-" {
+			continue
+		}
+
+		path := append(append([]int{}, parent...), i)
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+
+			if embedded.Kind() == reflect.Pointer {
+				// a pointer to an unexported struct type cannot be allocated
+				if !field.IsExported() {
+					continue
+				}
+
+				embedded = embedded.Elem()
+			}
+
+			if visited[embedded] {
+				continue
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				collectFields(embedded, path, visited, fields)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := tag
+
+		if name == "" {
+			name = toSnakeCase(field.Name)
+		}
+
+		name = strings.ToLower(name)
+
+		// fields of the outer struct take precedence over the fields of embedded structs
+		if existing, found := fields[name]; !found || len(existing) > len(path) {
+			fields[name] = path
+		}
+	}
+}
+
+// fieldByIndex returns a nested field of a given struct, allocating nil embedded struct pointers on the way.
+func fieldByIndex(value reflect.Value, path []int) reflect.Value {
+	for i, index := range path {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(index)
+	}
+
+	return value
+}
+
+// toSnakeCase converts a given field name to snake_case, e.g. "UserID" to "user_id".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+
+	var sb strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+
+		sb.WriteRune(unicode.ToLower(r))
+	}
+
+	return sb.String()
+}
+
+// QueryOne executes a given query and scans the first row into a struct of a given type, using the same mapping as QueryAll.
+// If the query returns no rows, sql.ErrNoRows is returned.
+func QueryOne[T any](ctx Context, query string, args ...interface{}) (T, error) {
+	// unlike sql.Rows, sql.Row does not expose columns, which are required to map them to fields
+	rows, err := ctx.Executor().QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return *new(T), err
+	}
+
+	defer rows.Close()
+
+	scan, err := newStructScanner[T](ctx, rows)
+
+	if err != nil {
+		return *new(T), err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return *new(T), err
+		}
+
+		return *new(T), sql.ErrNoRows
+	}
+
+	var value T
+
+	if err := scan(&value); err != nil {
+		return *new(T), err
+	}
+
+	return value, rows.Close()
+}
@@ -0,0 +1,151 @@
+package dbx_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+type (
+	Timestamps struct {
+		CreatedAt string
+		UpdatedAt string `db:"modified_at"`
+	}
+
+	account struct {
+		*Timestamps
+		ID       int64
+		UserName string `db:"login"`
+		Secret   string `db:"-"`
+	}
+
+	Node struct {
+		*Node
+		ID int64
+	}
+
+	event struct {
+		ID        int64
+		CreatedAt time.Time
+		DeletedAt *time.Time
+	}
+)
+
+func TestQueryAll(test *testing.T) {
+	test.Run("should scan rows into structs", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "login", "created_at", "modified_at"}).
+				AddRow(1, "john", "2023-01-01", "2023-01-02").
+				AddRow(2, "jane", "2023-02-01", "2023-02-02"),
+		).RowsWillBeClosed()
+
+		accounts, err := dbx.QueryAll[account](db.Context(context.Background()), "SELECT id, login, created_at, modified_at FROM accounts")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []account{
+			{ID: 1, UserName: "john", Timestamps: &Timestamps{CreatedAt: "2023-01-01", UpdatedAt: "2023-01-02"}},
+			{ID: 2, UserName: "jane", Timestamps: &Timestamps{CreatedAt: "2023-02-01", UpdatedAt: "2023-02-02"}},
+		}, accounts)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not follow self-referencing embedded pointers", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		nodes, err := dbx.QueryAll[Node](db.Context(context.Background()), "SELECT id FROM nodes")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Node{{ID: 1}}, nodes)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should fail on column without field", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "secret"}).AddRow(1, "password"),
+		)
+
+		_, err := dbx.QueryAll[account](db.Context(context.Background()), "SELECT id, secret FROM accounts")
+
+		assert.ErrorIs(t, err, dbx.ErrUnknownColumn)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestQueryOne(test *testing.T) {
+	test.Run("should scan first row into struct", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "login"}).AddRow(1, "john").AddRow(2, "jane"),
+		).RowsWillBeClosed()
+
+		acc, err := dbx.QueryOne[account](db.Context(context.Background()), "SELECT id, login FROM accounts")
+
+		assert.NoError(t, err)
+		assert.Equal(t, account{ID: 1, UserName: "john"}, acc)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should return sql.ErrNoRows", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "login"}))
+
+		acc, err := dbx.QueryOne[account](db.Context(context.Background()), "SELECT id, login FROM accounts WHERE id = 3")
+
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Equal(t, account{}, acc)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
+
+func TestWithScanLocation(test *testing.T) {
+	test.Run("should convert scanned times to location", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		loc := time.FixedZone("UTC+2", 2*60*60)
+		created := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+		deleted := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+
+		db := dbx.New(dbMock)
+		dmock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "created_at", "deleted_at"}).
+				AddRow(1, created, deleted).
+				AddRow(2, created, nil),
+		)
+
+		ctx := dbx.WithScanLocation(db.Context(context.Background()), loc)
+		events, err := dbx.QueryAll[event](ctx, "SELECT id, created_at, deleted_at FROM events")
+
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, loc, events[0].CreatedAt.Location())
+		assert.True(t, created.Equal(events[0].CreatedAt))
+		assert.Equal(t, loc, events[0].DeletedAt.Location())
+		assert.True(t, deleted.Equal(*events[0].DeletedAt))
+		assert.Nil(t, events[1].DeletedAt)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
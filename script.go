@@ -0,0 +1,166 @@
+package dbx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExecFile reads SQL statements separated by semicolons from a given reader and executes them one by one.
+// Semicolons inside string literals, quoted identifiers, comments and dollar-quoted strings (e.g. Postgres function bodies) do not split statements.
+// Quotes are escaped by doubling them; backslash escapes are not recognized.
+// Execution stops at the first failed statement.
+func ExecFile(ctx Context, r io.Reader) error {
+	script, err := io.ReadAll(r)
+
+	if err != nil {
+		return err
+	}
+
+	for i, statement := range splitStatements(string(script)) {
+		if _, err := ctx.Executor().ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// splitStatements splits a given SQL script into statements.
+// Statements that consist of whitespaces and comments only are skipped.
+func splitStatements(script string) []string {
+	statements := make([]string, 0)
+	start := 0
+	significant := false
+
+	for i := 0; i < len(script); {
+		c := script[i]
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i)
+			significant = true
+		case strings.HasPrefix(script[i:], "--"):
+			i = skipLineComment(script, i)
+		case strings.HasPrefix(script[i:], "/*"):
+			i = skipBlockComment(script, i)
+		case c == '$':
+			i = skipDollarQuoted(script, i)
+			significant = true
+		case c == ';':
+			if significant {
+				statements = append(statements, strings.TrimSpace(script[start:i]))
+			}
+
+			i++
+			start = i
+			significant = false
+		default:
+			if !isSpace(c) {
+				significant = true
+			}
+
+			i++
+		}
+	}
+
+	if significant {
+		statements = append(statements, strings.TrimSpace(script[start:]))
+	}
+
+	return statements
+}
+
+// skipQuoted returns the position after a quoted string or identifier starting at a given position.
+func skipQuoted(script string, pos int) int {
+	quote := script[pos]
+
+	for i := pos + 1; i < len(script); i++ {
+		if script[i] != quote {
+			continue
+		}
+
+		// doubled quote is an escaped quote
+		if i+1 < len(script) && script[i+1] == quote {
+			i++
+
+			continue
+		}
+
+		return i + 1
+	}
+
+	return len(script)
+}
+
+// skipLineComment returns the position after a line comment starting at a given position.
+func skipLineComment(script string, pos int) int {
+	end := strings.IndexByte(script[pos:], '\n')
+
+	if end < 0 {
+		return len(script)
+	}
+
+	return pos + end + 1
+}
+
+// skipBlockComment returns the position after a possibly nested block comment starting at a given position.
+func skipBlockComment(script string, pos int) int {
+	depth := 0
+
+	for i := pos; i < len(script)-1; i++ {
+		switch script[i : i+2] {
+		case "/*":
+			depth++
+			i++
+		case "*/":
+			depth--
+			i++
+
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return len(script)
+}
+
+// skipDollarQuoted returns the position after a dollar-quoted string starting at a given position.
+// If the dollar sign does not start a dollar-quoted string, e.g. it is a positional parameter, the next position is returned.
+func skipDollarQuoted(script string, pos int) int {
+	if pos > 0 && isIdentifier(script[pos-1]) {
+		return pos + 1
+	}
+
+	end := pos + 1
+
+	for end < len(script) && isIdentifier(script[end]) && script[end] != '$' {
+		end++
+	}
+
+	if end >= len(script) || script[end] != '$' || (end > pos+1 && isDigit(script[pos+1])) {
+		return pos + 1
+	}
+
+	tag := script[pos : end+1]
+	closing := strings.Index(script[end+1:], tag)
+
+	if closing < 0 {
+		return len(script)
+	}
+
+	return end + 1 + closing + len(tag)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
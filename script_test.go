@@ -0,0 +1,72 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestExecFile(test *testing.T) {
+	test.Run("should execute each statement", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer dbMock.Close()
+
+		script := `
+-- users; the main table
+CREATE TABLE users (id INT, name TEXT DEFAULT 'a;b', "odd;column" INT);
+
+/* trigger; /* nested; */ function */
+CREATE FUNCTION touch() RETURNS trigger AS $body$
+BEGIN
+	NEW.updated_at = now();
+	RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;
+
+CREATE FUNCTION add(a INT, b INT) RETURNS INT AS $$ SELECT $1 + $2; $$ LANGUAGE sql;
+
+INSERT INTO users (id, name) VALUES (1, 'it''s; fine')
+;
+-- trailing; comment
+`
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec(`-- users; the main table
+CREATE TABLE users (id INT, name TEXT DEFAULT 'a;b', "odd;column" INT)`).WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec(`/* trigger; /* nested; */ function */
+CREATE FUNCTION touch() RETURNS trigger AS $body$
+BEGIN
+	NEW.updated_at = now();
+	RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql`).WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec(`CREATE FUNCTION add(a INT, b INT) RETURNS INT AS $$ SELECT $1 + $2; $$ LANGUAGE sql`).WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec(`INSERT INTO users (id, name) VALUES (1, 'it''s; fine')`).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := dbx.ExecFile(db.Context(context.Background()), strings.NewReader(script))
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should stop at the first failed statement", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer dbMock.Close()
+
+		testErr := errors.New("syntax error")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SELEC 2").WillReturnError(testErr)
+
+		err := dbx.ExecFile(db.Context(context.Background()), strings.NewReader("SELECT 1; SELEC 2; SELECT 3;"))
+
+		assert.ErrorIs(t, err, testErr)
+		assert.EqualError(t, err, "statement 2: syntax error")
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,98 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// connExecutor is an executor bound to a single connection.
+type connExecutor struct {
+	conn *sql.Conn
+}
+
+// SessionContext acquires a dedicated connection, creates a context bound to it and runs a given setup function,
+// e.g. to apply session-level SET statements.
+// All operations performed with the returned context, including transactions started by Transaction, use the same connection.
+// The returned release function must be called to return the connection to the pool.
+// Note that session settings outlive the release, since the connection is reused by the pool.
+func SessionContext(ctx context.Context, db Database, setup func(Context) error) (Context, func(), error) {
+	conn, err := db.Conn(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() {
+		conn.Close()
+	}
+
+	sessionCtx := NewContext(ctx, &connExecutor{conn})
+
+	if setup != nil {
+		if err := setup(sessionCtx); err != nil {
+			release()
+
+			return nil, nil, err
+		}
+	}
+
+	return sessionCtx, release, nil
+}
+
+// WithConn acquires a dedicated connection, runs a given function with a context bound to it
+// and returns the connection to the pool once the function returns or panics.
+// It is a scoped alternative to SessionContext for session-level work, e.g. advisory locks or temporary tables.
+func WithConn(ctx context.Context, db Database, fn func(Context) error) error {
+	sessionCtx, release, err := SessionContext(ctx, db, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return fn(sessionCtx)
+}
+
+// beginnerFrom returns a connection bound to a given context or the database if there is none.
+func beginnerFrom(ctx context.Context, db Database) Beginner {
+	if found := FromContext(ctx); found != nil {
+		if conn, ok := unwrapHooks(found.Executor()).(*connExecutor); ok {
+			return conn
+		}
+	}
+
+	return db
+}
+
+func (c *connExecutor) Begin() (*sql.Tx, error) {
+	return c.conn.BeginTx(context.Background(), nil)
+}
+
+func (c *connExecutor) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.conn.BeginTx(ctx, opts)
+}
+
+func (c *connExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.conn.ExecContext(context.Background(), query, args...)
+}
+
+func (c *connExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn.QueryContext(context.Background(), query, args...)
+}
+
+func (c *connExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.conn.QueryRowContext(context.Background(), query, args...)
+}
+
+func (c *connExecutor) ExecContext(dbContext context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.conn.ExecContext(dbContext, query, args...)
+}
+
+func (c *connExecutor) QueryContext(dbContext context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.conn.QueryContext(dbContext, query, args...)
+}
+
+func (c *connExecutor) QueryRowContext(dbContext context.Context, query string, args ...interface{}) *sql.Row {
+	return c.conn.QueryRowContext(dbContext, query, args...)
+}
@@ -0,0 +1,111 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestSessionContext(test *testing.T) {
+	test.Run("should use the same connection for all queries", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("SET search_path").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		ctx, release, err := dbx.SessionContext(context.Background(), db, func(c dbx.Context) error {
+			_, e := c.Executor().Exec("SET search_path TO tenant")
+
+			return e
+		})
+
+		assert.NoError(t, err)
+
+		_, err = ctx.Executor().Exec("SELECT 1")
+		assert.NoError(t, err)
+
+		err = dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			_, e := c.Executor().Exec("SELECT 2")
+
+			return e
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, dbMock.Stats().OpenConnections)
+		assert.Equal(t, 1, dbMock.Stats().InUse)
+
+		release()
+
+		assert.Equal(t, 0, dbMock.Stats().InUse)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should release connection when setup fails", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("SET role").WillReturnError(testErr)
+
+		_, _, err := dbx.SessionContext(context.Background(), db, func(c dbx.Context) error {
+			_, e := c.Executor().Exec("SET role admin")
+
+			return e
+		})
+
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, 0, dbMock.Stats().InUse)
+	})
+}
+
+func TestWithConn(test *testing.T) {
+	test.Run("should release connection after function returns", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("CREATE TEMPORARY TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("INSERT INTO tmp").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := dbx.WithConn(context.Background(), db, func(c dbx.Context) error {
+			if _, err := c.Executor().Exec("CREATE TEMPORARY TABLE tmp (id int)"); err != nil {
+				return err
+			}
+
+			_, err := c.Executor().Exec("INSERT INTO tmp VALUES (1)")
+
+			assert.Equal(t, 1, dbMock.Stats().InUse)
+
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, dbMock.Stats().InUse)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should release connection when function panics", func(t *testing.T) {
+		dbMock, _, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		assert.Panics(t, func() {
+			dbx.WithConn(context.Background(), db, func(c dbx.Context) error {
+				panic("boom")
+			})
+		})
+
+		assert.Equal(t, 0, dbMock.Stats().InUse)
+	})
+}
@@ -0,0 +1,60 @@
+package dbx
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// flights holds a group of calls per result type, so that calls with different result types never share a result.
+// The groups are process-global, therefore calls with the same key are coalesced across databases.
+var flights sync.Map
+
+// flightPanic carries a panic of a flight to the calls waiting for it.
+type flightPanic struct {
+	value interface{}
+}
+
+func (p *flightPanic) Error() string {
+	return "single flight panicked"
+}
+
+// singleFlight performs a given function once for concurrent calls with the same key.
+// Each call waits for the result until its own context is done.
+// A panic of the function is propagated to every call waiting for it.
+func singleFlight[T any](ctx context.Context, key string, fn func() (T, error)) (T, error) {
+	group, _ := flights.LoadOrStore(reflect.TypeOf((*T)(nil)).Elem(), new(singleflight.Group))
+
+	ch := group.(*singleflight.Group).DoChan(key, func() (out interface{}, err error) {
+		// the function runs on its own goroutine, where a panic would crash the process
+		defer func() {
+			if r := recover(); r != nil {
+				out, err = nil, &flightPanic{r}
+			}
+		}()
+
+		return fn()
+	})
+
+	select {
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
+	case res := <-ch:
+		if p, ok := res.Err.(*flightPanic); ok {
+			panic(p.value)
+		}
+
+		if res.Err != nil {
+			return *new(T), res.Err
+		}
+
+		// the result is nil when T is an interface
+		if res.Val == nil {
+			return *new(T), nil
+		}
+
+		return res.Val.(T), nil
+	}
+}
@@ -0,0 +1,193 @@
+package dbx_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+// waitingContext signals once a call starts waiting for the context to be done,
+// which a single flight call does only after it has joined the flight.
+type waitingContext struct {
+	context.Context
+	waiting chan struct{}
+	once    sync.Once
+}
+
+func newWaitingContext() *waitingContext {
+	return &waitingContext{Context: context.Background(), waiting: make(chan struct{})}
+}
+
+func (c *waitingContext) Done() <-chan struct{} {
+	c.once.Do(func() {
+		close(c.waiting)
+	})
+
+	return c.Context.Done()
+}
+
+func TestWithSingleFlight(test *testing.T) {
+	key := dbx.WithSingleFlight(func(ctx context.Context) string {
+		return "refresh-rates"
+	})
+
+	test.Run("should run concurrent transactions with the same key once", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		var calls atomic.Int32
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		op := func(c dbx.Context) (int, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+			}
+
+			<-release
+
+			return 42, nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]int, 2)
+		errs := make([]error, 2)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[0], errs[0] = dbx.TransactionWithResult(context.Background(), db, op, key)
+		}()
+
+		<-started
+
+		ctx := newWaitingContext()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[1], errs[1] = dbx.TransactionWithResult(ctx, db, op, key)
+		}()
+
+		<-ctx.waiting
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, []int{42, 42}, results)
+		assert.Equal(t, []error{nil, nil}, errs)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should stop waiting once the context is done", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		var calls atomic.Int32
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			calls.Add(1)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			// the first call is in flight, so the second one joins it instead of running
+			return dbx.Transaction(ctx, db, func(c dbx.Context) error {
+				calls.Add(1)
+
+				return nil
+			}, key)
+		}, key)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should propagate panic to every call", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		op := func(c dbx.Context) error {
+			close(started)
+			<-release
+
+			panic("boom")
+		}
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			assert.PanicsWithValue(t, "boom", func() {
+				dbx.Transaction(context.Background(), db, op, key)
+			})
+		}()
+
+		<-started
+
+		ctx := newWaitingContext()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			assert.PanicsWithValue(t, "boom", func() {
+				dbx.Transaction(ctx, db, op, key)
+			})
+		}()
+
+		<-ctx.waiting
+		close(release)
+		wg.Wait()
+
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not coalesce transactions with an empty key", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		empty := dbx.WithSingleFlight(func(ctx context.Context) string {
+			return ""
+		})
+
+		for i := 0; i < 2; i++ {
+			err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+				return nil
+			}, empty)
+
+			assert.NoError(t, err)
+		}
+
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
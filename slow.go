@@ -0,0 +1,22 @@
+package dbx
+
+import (
+	"context"
+	"time"
+)
+
+// WithSlowQueryHook wraps a given executor, so that statements taking longer than a given threshold are passed to a given function.
+// It is a lightweight alternative to WithLogging for alerting on pathological statements.
+// The results and errors of the statements are returned unchanged.
+func WithSlowQueryHook(exec Executor, threshold time.Duration, fn func(query string, args []interface{}, duration time.Duration)) Executor {
+	return withHook(exec, func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error {
+		started := time.Now()
+		err := next(ctx)
+
+		if duration := time.Since(started); duration > threshold {
+			fn(stmt.logged(), stmt.args, duration)
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,38 @@
+package dbx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestWithSlowQueryHook(test *testing.T) {
+	test.Run("should report only statements exceeding threshold", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectExec("DELETE FROM logs").WillDelayFor(50 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		var slow []string
+
+		exec := dbx.WithSlowQueryHook(db, 20*time.Millisecond, func(query string, args []interface{}, duration time.Duration) {
+			assert.GreaterOrEqual(t, duration, 50*time.Millisecond)
+
+			slow = append(slow, query)
+		})
+
+		_, err := exec.Exec("UPDATE users SET active = true")
+		assert.NoError(t, err)
+
+		_, err = exec.Exec("DELETE FROM logs")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"DELETE FROM logs"}, slow)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,101 @@
+package dbx
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Outcome describes how a transaction was completed.
+	Outcome string
+
+	// Summary describes what a transaction did to the database.
+	Summary struct {
+		// Statements is the number of statements executed by the operation.
+		Statements int64
+		// RowsAffected is the total number of rows affected by the executed statements.
+		// Statements whose driver does not report affected rows are not taken into account.
+		RowsAffected int64
+		// Duration is the time spent on the transaction, including begin and commit or rollback.
+		Duration time.Duration
+		// Outcome describes how the transaction was completed.
+		Outcome Outcome
+		// Retries is the number of times the operation was re-run within a new transaction, see WithRetry.
+		// Statements and RowsAffected describe the last attempt only.
+		Retries int
+	}
+
+	summaryRecorder struct {
+		summary *Summary
+		started time.Time
+		counter *countingTransactor
+	}
+)
+
+const (
+	// OutcomeCommitted means that a new transaction was created and committed, or a savepoint was released.
+	OutcomeCommitted Outcome = "committed"
+	// OutcomeRolledBack means that a new transaction was created and rolled back due to an operation error,
+	// or the transaction was rolled back to a savepoint.
+	OutcomeRolledBack Outcome = "rolled_back"
+	// OutcomeReused means that the operation was performed within an existing transaction,
+	// which is committed or rolled back by its owner.
+	OutcomeReused Outcome = "reused"
+	// OutcomeFailed means that the transaction or savepoint could not be started or committed.
+	OutcomeFailed Outcome = "failed"
+)
+
+// TransactionWithSummary begins or reuses a transaction, passes the context to a given receiver, handles the commit or rollback
+// and returns a summary of what the transaction did.
+// Statement statistics are collected only by this function, so Transaction and TransactionWithResult have no additional overhead.
+func TransactionWithSummary(ctx context.Context, db Database, op Operation, opts ...Option) (Summary, error) {
+	var summary Summary
+
+	_, err := transactionWithInternal(ctx, db, func(ctx Context) (interface{}, error) {
+		return nil, op(ctx)
+	}, append([]Option{withSummary(&summary)}, opts...))
+
+	return summary, err
+}
+
+func withSummary(summary *Summary) Option {
+	return func(opts *options) {
+		opts.summary = &summaryRecorder{
+			summary: summary,
+			started: time.Now(),
+		}
+	}
+}
+
+// track wraps a given transactor in order to collect statement statistics.
+func (r *summaryRecorder) track(tx Transactor) Transactor {
+	r.counter = &countingTransactor{Transactor: tx}
+
+	return r.counter
+}
+
+// retry records the number of retries made so far.
+// It is safe to call on a nil recorder.
+func (r *summaryRecorder) retry(retries int) {
+	if r == nil {
+		return
+	}
+
+	r.summary.Retries = retries
+}
+
+// finish completes the summary with a given outcome.
+// It is safe to call on a nil recorder.
+func (r *summaryRecorder) finish(outcome Outcome) {
+	if r == nil {
+		return
+	}
+
+	r.summary.Outcome = outcome
+	r.summary.Duration = time.Since(r.started)
+
+	if r.counter != nil {
+		r.summary.Statements = r.counter.statements.Load()
+		r.summary.RowsAffected = r.counter.rowsAffected.Load()
+	}
+}
@@ -0,0 +1,130 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+)
+
+func TestTransactionWithSummary(test *testing.T) {
+	test.Run("should summarize committed transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 2))
+		dmock.ExpectExec("INSERT INTO logs").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectCommit()
+
+		summary, err := dbx.TransactionWithSummary(context.Background(), db, func(c dbx.Context) error {
+			if _, e := c.Executor().Exec("UPDATE users"); e != nil {
+				return e
+			}
+
+			_, e := c.Executor().Exec("INSERT INTO logs")
+
+			return e
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+		assert.Equal(t, int64(2), summary.Statements)
+		assert.Equal(t, int64(3), summary.RowsAffected)
+		assert.Equal(t, dbx.OutcomeCommitted, summary.Outcome)
+		assert.Greater(t, summary.Duration.Nanoseconds(), int64(0))
+	})
+
+	test.Run("should summarize rolled back transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 2))
+		dmock.ExpectRollback()
+
+		summary, err := dbx.TransactionWithSummary(context.Background(), db, func(c dbx.Context) error {
+			c.Executor().Exec("UPDATE users")
+
+			return testErr
+		})
+
+		assert.Equal(t, testErr, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+		assert.Equal(t, int64(1), summary.Statements)
+		assert.Equal(t, dbx.OutcomeRolledBack, summary.Outcome)
+	})
+
+	test.Run("should summarize reused transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectExec("SELECT 2").WillReturnResult(sqlmock.NewResult(0, 0))
+		dmock.ExpectCommit()
+
+		var inner dbx.Summary
+
+		outer, err := dbx.TransactionWithSummary(context.Background(), db, func(c dbx.Context) error {
+			c.Executor().Exec("SELECT 1")
+
+			var e error
+
+			inner, e = dbx.TransactionWithSummary(c, db, func(c dbx.Context) error {
+				_, e := c.Executor().Exec("SELECT 2")
+
+				return e
+			})
+
+			return e
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+		assert.Equal(t, int64(1), inner.Statements)
+		assert.Equal(t, dbx.OutcomeReused, inner.Outcome)
+		assert.Equal(t, int64(2), outer.Statements)
+		assert.Equal(t, dbx.OutcomeCommitted, outer.Outcome)
+	})
+
+	test.Run("should count retries", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("serialization failure")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		attempts := 0
+
+		summary, err := dbx.TransactionWithSummary(context.Background(), db, func(c dbx.Context) error {
+			attempts++
+
+			if attempts < 3 {
+				return testErr
+			}
+
+			return nil
+		}, dbx.WithRetry(3), dbx.WithRetryPolicy(func(err error) bool {
+			return true
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, summary.Retries)
+		assert.Equal(t, dbx.OutcomeCommitted, summary.Outcome)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,71 @@
+package dbx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	tracingOptions struct {
+		Redact func(query string) string
+	}
+
+	// TracingOption configures an executor created by WithTracing.
+	TracingOption func(opts *tracingOptions)
+)
+
+// WithStatementRedaction sets a function that redacts a statement before it is recorded as the db.statement attribute,
+// e.g. Fingerprint to strip literals that may contain sensitive data.
+func WithStatementRedaction(redact func(query string) string) TracingOption {
+	return func(opts *tracingOptions) {
+		opts.Redact = redact
+	}
+}
+
+// WithTracing wraps a given executor, so that every statement it executes is recorded as a "db.query" span
+// with the db.statement attribute and the error status if the statement fails.
+// Statements of an operation named via Named have the db.operation attribute as well.
+// Context executor methods start the span as a child of the span in the passed context,
+// while non-context methods start a root span.
+// Transactions started from a context bound to the returned executor are traced as well.
+func WithTracing(exec Executor, tracer trace.Tracer, setters ...TracingOption) Executor {
+	opts := &tracingOptions{}
+
+	for _, setter := range setters {
+		setter(opts)
+	}
+
+	return withHook(exec, func(ctx context.Context, stmt statement, next func(ctx context.Context) error) error {
+		query := stmt.query
+
+		if opts.Redact != nil {
+			query = opts.Redact(query)
+		}
+
+		query = truncateQuery(query, stmt.maxLength)
+
+		attrs := []attribute.KeyValue{attribute.String("db.statement", query)}
+
+		if name := OperationName(ctx); name != "" {
+			attrs = append(attrs, attribute.String("db.operation", name))
+		}
+
+		ctx, span := tracer.Start(ctx, "db.query",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		err := next(ctx)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return err
+	})
+}
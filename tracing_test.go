@@ -0,0 +1,82 @@
+package dbx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziflex/dbx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	recordingTracer struct {
+		trace.Tracer
+		spans []*recordingSpan
+	}
+
+	recordingSpan struct {
+		trace.Span
+		name   string
+		attrs  []attribute.KeyValue
+		status codes.Code
+		ended  bool
+	}
+)
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, noop := t.Tracer.Start(ctx, name, opts...)
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &recordingSpan{
+		Span:  noop,
+		name:  name,
+		attrs: cfg.Attributes(),
+	}
+	t.spans = append(t.spans, span)
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.status = code
+}
+
+func (s *recordingSpan) End(options ...trace.SpanEndOption) {
+	s.ended = true
+}
+
+func TestWithTracing(test *testing.T) {
+	test.Run("should record span per statement", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+		dmock.ExpectQuery("SELECT name").WillReturnError(testErr)
+
+		tracer := &recordingTracer{Tracer: trace.NewNoopTracerProvider().Tracer("test")}
+		exec := dbx.WithTracing(db, tracer, dbx.WithStatementRedaction(dbx.Fingerprint))
+
+		_, err := exec.ExecContext(context.Background(), "UPDATE users SET name = 'John' WHERE id = 1")
+		assert.NoError(t, err)
+
+		_, err = exec.Query("SELECT name FROM users")
+		assert.Equal(t, testErr, err)
+
+		assert.Len(t, tracer.spans, 2)
+
+		assert.Equal(t, "db.query", tracer.spans[0].name)
+		assert.Equal(t, []attribute.KeyValue{attribute.String("db.statement", "update users set name=? where id=?")}, tracer.spans[0].attrs)
+		assert.Equal(t, codes.Unset, tracer.spans[0].status)
+		assert.True(t, tracer.spans[0].ended)
+
+		assert.Equal(t, codes.Error, tracer.spans[1].status)
+		assert.True(t, tracer.spans[1].ended)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+}
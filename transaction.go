@@ -2,10 +2,16 @@ package dbx
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
 )
 
 // Transaction begins or reuses a transaction, passes the context to a given receiver and handles the commit or rollback.
 // Note: if the context is a transaction context, the transaction will be reused.
+// Any panic during operation execution triggers rollback if a new transaction was created, and is then propagated.
 func Transaction(ctx context.Context, db Database, op Operation, opts ...Option) error {
 	_, err := transactionWithInternal(ctx, db, func(ctx Context) (interface{}, error) {
 		return nil, op(ctx)
@@ -19,11 +25,73 @@ func TransactionWithResult[T any](ctx context.Context, db Database, op Operation
 	return transactionWithInternal(ctx, db, op, setters)
 }
 
+// Then performs two operations within a single transaction, passing the result of the first operation to the second one.
+func Then[A, B any](ctx context.Context, db Database, first OperationWithResult[A], second func(ctx Context, in A) (B, error), setters ...Option) (B, error) {
+	return transactionWithInternal(ctx, db, func(ctx Context) (B, error) {
+		in, err := first(ctx)
+
+		if err != nil {
+			return *new(B), err
+		}
+
+		return second(ctx, in)
+	}, setters)
+}
+
 func transactionWithInternal[T any](ctx context.Context, db Database, op OperationWithResult[T], setters []Option) (T, error) {
+	opts, err := newOptions(setters)
+
+	if err != nil {
+		return *new(T), err
+	}
+
+	run := func() (T, error) {
+		return retryTransaction(ctx, db, op, opts)
+	}
+
+	if opts.SingleFlightKey != nil {
+		if key := opts.SingleFlightKey(ctx); key != "" {
+			return singleFlight(ctx, key, run)
+		}
+	}
+
+	return run()
+}
+
+// retryTransaction runs the operation and re-runs it within a new transaction while it fails with a retriable error.
+// Operations performed within a reused transaction are never retried, since their transaction is owned by the caller.
+func retryTransaction[T any](ctx context.Context, db Database, op OperationWithResult[T], opts *options) (T, error) {
+	for attempt := 1; ; attempt++ {
+		opts.summary.retry(attempt - 1)
+
+		out, created, err := runTransaction(ctx, db, op, opts)
+
+		if err == nil || !created || attempt >= opts.MaxAttempts || ctx.Err() != nil || !opts.RetryPolicy(err) {
+			return out, err
+		}
+
+		if opts.Backoff != nil {
+			timer := time.NewTimer(opts.Backoff(attempt - 1))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return *new(T), ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func runTransaction[T any](ctx context.Context, db Database, op OperationWithResult[T], opts *options) (T, bool, error) {
 	var tx Transactor
 	var createdTx bool
 	var dbCtx Context
-	opts := newOptions(setters)
+	var err error
+	var release func()
+	var beginCtx context.Context
+	var finished *atomic.Bool
 
 	if !opts.AlwaysCreate {
 		// retrieve existing or create a new context
@@ -40,35 +108,236 @@ func transactionWithInternal[T any](ctx context.Context, db Database, op Operati
 	}
 
 	if tx == nil {
-		var err error
 		createdTx = true
 
-		// create a new transaction
-		tx, err = db.BeginTx(ctx, opts.TxOptions)
+		// derive a cancellable context, so that the transaction can be aborted via Context.Cancel
+		txCtx, cancel := context.WithCancelCause(ctx)
+		cancelTimeout := context.CancelFunc(func() {})
+
+		if deadline, ok := opts.deadline(ctx); ok {
+			txCtx, cancelTimeout = context.WithDeadline(txCtx, deadline)
+		}
+
+		beginCtx = txCtx
+
+		if opts.CommitDespiteDeadline {
+			// database/sql rolls back transactions whose context is done,
+			// so the transaction is begun on a context that is done only via Context.Cancel
+			var cancelBegin context.CancelCauseFunc
+			beginCtx, cancelBegin = context.WithCancelCause(valuesContext{ctx})
+			cancelTx := cancel
+			cancel = func(cause error) {
+				cancelTx(cause)
+				cancelBegin(cause)
+			}
+		}
+
+		// the transaction is finished once it is committed or rolled back, which is when the context is released
+		finished = new(atomic.Bool)
+		free := func() {}
+		release = func() {
+			finished.Store(true)
+			cancelTimeout()
+			cancel(nil)
+			free()
+		}
+
+		defer func() { release() }()
+
+		if limiter, ok := db.(transactionLimiter); ok {
+			// hold a slot of the database until the transaction is finished
+			free, err = limiter.acquireTransaction(ctx)
+
+			if err != nil {
+				free = func() {}
+				opts.summary.finish(OutcomeFailed)
+
+				return *new(T), createdTx, err
+			}
+		}
+
+		// create a new transaction, on the session connection if the context is bound to one
+		tx, err = opts.Manager.Begin(beginCtx, beginnerFrom(ctx, db), opts.txOptions(ctx))
 
 		if err != nil {
-			return *new(T), err
+			opts.summary.finish(OutcomeFailed)
+
+			return *new(T), createdTx, err
 		}
 
-		// create a new context with the transaction
-		dbCtx = NewContext(ctx, tx)
+		defer func() {
+			// roll back the transaction if the operation panics and let the panic propagate
+			if r := recover(); r != nil {
+				opts.Manager.Rollback(ctx, tx)
+
+				panic(r)
+			}
+		}()
+
+		// guard the transaction from being committed or rolled back by the operation
+		var exec Transactor = &guardedTransactor{tx, opts.CommitGuard}
+
+		if found := FromContext(ctx); found != nil {
+			// apply the hooks of the context executor to the transaction
+			exec = inheritHooks(found.Executor(), exec)
+		}
+
+		// create a new context with the transaction, collecting callbacks registered via RegisterAfterCommit
+		dbCtx = SetValue(newTxContext(txCtx, exec, cancel, finished), &afterCommit{})
+		dbCtx = SetValue(dbCtx, savepointExecutor{exec})
+
+		if deadline, ok := opts.operationDeadline(txCtx); ok {
+			// limit the operation only, so that the transaction outlives its deadline and can still be committed
+			var cancelOperation context.CancelFunc
+			dbCtx, cancelOperation = dbCtx.WithDeadline(deadline)
+			defer cancelOperation()
+		}
+	}
+
+	var savepoint string
+	var parentCallbacks *afterCommit
+
+	if !createdTx && opts.Savepoint {
+		// isolate the operation from the rest of the existing transaction
+		dbCtx, savepoint, err = beginSavepoint(dbCtx, tx)
+
+		if err != nil {
+			opts.summary.finish(OutcomeFailed)
+
+			return *new(T), createdTx, err
+		}
+
+		// collect callbacks registered within the savepoint separately, so that they can be discarded
+		if callbacks, ok := GetValue[*afterCommit](dbCtx); ok {
+			parentCallbacks = callbacks
+			dbCtx = SetValue(dbCtx, &afterCommit{})
+		}
+	}
+
+	if opts.summary != nil {
+		// collect statistics of the statements executed by the operation
+		dbCtx = withExecutor(dbCtx, opts.summary.track(dbCtx.Executor().(Transactor)))
+	}
+
+	if opts.MaxStatements > 0 {
+		// reject statements beyond the limit
+		dbCtx = withExecutor(dbCtx, &countingTransactor{
+			Transactor: dbCtx.Executor().(Transactor),
+			limit:      int64(opts.MaxStatements),
+		})
+	}
+
+	var counter *countingTransactor
+
+	if createdTx && opts.RollbackEmpty {
+		// count statements to tell whether there is anything to commit
+		counter = &countingTransactor{Transactor: dbCtx.Executor().(Transactor)}
+		dbCtx = withExecutor(dbCtx, counter)
 	}
 
 	out, err := op(dbCtx)
 
 	if err != nil {
-		if createdTx {
-			tx.Rollback()
+		if createdTx && opts.ManualCleanup != nil {
+			// hand the open transaction over to the caller, its context is released once the caller finalizes it
+			opts.ManualCleanup(&releasingTransactor{Transactor: tx, release: release})
+			release = func() {}
+		} else if createdTx {
+			err = joinRollbackError(err, opts.Manager.Rollback(ctx, tx))
+			finished.Store(true)
+			opts.summary.finish(OutcomeRolledBack)
+			opts.rolledBack(err)
+		} else if savepoint != "" {
+			err = joinRollbackError(err, rollbackToSavepoint(dbCtx, tx, savepoint))
+			opts.summary.finish(OutcomeRolledBack)
+		} else {
+			opts.summary.finish(OutcomeReused)
 		}
 
-		return *new(T), err
+		return *new(T), createdTx, err
 	}
 
 	if createdTx {
-		if e := tx.Commit(); e != nil {
-			return *new(T), e
+		// do not commit if the operation context is already done, e.g. its deadline has passed,
+		// unless the commit is forced and the transaction itself is not cancelled
+		if dbCtx.Err() != nil && (!opts.CommitDespiteDeadline || beginCtx.Err() != nil) {
+			err = joinRollbackError(context.Cause(dbCtx), opts.Manager.Rollback(ctx, tx))
+			finished.Store(true)
+			opts.summary.finish(OutcomeRolledBack)
+			opts.rolledBack(err)
+
+			return *new(T), createdTx, err
 		}
+
+		if counter != nil && counter.statements.Load() == 0 {
+			// nothing was executed, so the transaction is rolled back instead of being committed
+			if e := opts.Manager.Rollback(ctx, tx); e != nil && !errors.Is(e, sql.ErrTxDone) {
+				opts.summary.finish(OutcomeFailed)
+
+				return *new(T), createdTx, fmt.Errorf("%w: %w", ErrRollback, e)
+			}
+		} else if e := opts.Manager.Commit(ctx, tx); e != nil {
+			opts.summary.finish(OutcomeFailed)
+
+			return *new(T), createdTx, fmt.Errorf("%w: %w", ErrCommit, e)
+		}
+
+		// the transaction is no longer active within the callbacks
+		finished.Store(true)
+		opts.summary.finish(OutcomeCommitted)
+		opts.committed()
+
+		if callbacks, ok := GetValue[*afterCommit](dbCtx); ok {
+			for _, fn := range callbacks.drain() {
+				fn()
+			}
+		}
+	} else if savepoint != "" {
+		if e := releaseSavepoint(dbCtx, tx, savepoint); e != nil {
+			opts.summary.finish(OutcomeFailed)
+
+			return *new(T), createdTx, fmt.Errorf("%w: %w", ErrReleaseSavepoint, e)
+		}
+
+		opts.summary.finish(OutcomeCommitted)
+
+		if parentCallbacks != nil {
+			// keep the callbacks registered within the savepoint until the transaction is committed
+			callbacks, _ := GetValue[*afterCommit](dbCtx)
+			parentCallbacks.add(callbacks.drain()...)
+		}
+	} else {
+		opts.summary.finish(OutcomeReused)
+	}
+
+	return out, createdTx, nil
+}
+
+// joinRollbackError joins a given error with the error of the rollback it caused, if any.
+// The transaction being already done is not considered a rollback failure,
+// since database/sql rolls back transactions whose context is done on its own.
+func joinRollbackError(err, rollbackErr error) error {
+	if rollbackErr == nil || errors.Is(rollbackErr, sql.ErrTxDone) {
+		return err
 	}
 
-	return out, nil
+	return errors.Join(err, fmt.Errorf("%w: %w", ErrRollback, rollbackErr))
+}
+
+// releasingTransactor calls a given function once the transaction is committed or rolled back.
+type releasingTransactor struct {
+	Transactor
+	release func()
+}
+
+func (t *releasingTransactor) Commit() error {
+	defer t.release()
+
+	return t.Transactor.Commit()
+}
+
+func (t *releasingTransactor) Rollback() error {
+	defer t.release()
+
+	return t.Transactor.Rollback()
 }
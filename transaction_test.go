@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -116,6 +117,62 @@ func TestTransaction(test *testing.T) {
 		assert.Equal(t, testErr, err)
 	})
 
+	test.Run("should wrap commit errors", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit().WillReturnError(testErr)
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, dbx.ErrCommit)
+		assert.ErrorIs(t, err, testErr)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should preserve operation error when rollback fails", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		opErr := errors.New("operation error")
+		rollbackErr := errors.New("connection lost")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback().WillReturnError(rollbackErr)
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return opErr
+		})
+
+		assert.ErrorIs(t, err, opErr)
+		assert.ErrorIs(t, err, dbx.ErrRollback)
+		assert.ErrorIs(t, err, rollbackErr)
+		assert.NotErrorIs(t, err, dbx.ErrCommit)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should rollback and re-panic when operation panics", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+				panic("boom")
+			})
+		})
+
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
 	test.Run("should reuse nested transaction", func(t *testing.T) {
 		dbMock, dmock, _ := sqlmock.New()
 		defer dbMock.Close()
@@ -167,4 +224,216 @@ func TestTransaction(test *testing.T) {
 
 		assert.NoError(t, err)
 	})
+
+	test.Run("should reuse transactor provided via context", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectCommit()
+
+		tx, err := dbMock.Begin()
+		assert.NoError(t, err)
+
+		ctx := dbx.NewTransactorContext(context.Background(), tx)
+
+		err = dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			assert.Equal(t, tx, c.Executor())
+
+			_, e := c.Executor().Exec("SELECT 1")
+
+			return e
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should create independent nested transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+		dmock.ExpectCommit()
+
+		err := dbx.Transaction(context.Background(), db, func(c1 dbx.Context) error {
+			return dbx.Transaction(c1, db, func(c2 dbx.Context) error {
+				assert.NotEqual(t, c1.Executor(), c2.Executor())
+
+				return nil
+			}, dbx.WithIndependentTransaction())
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should fail on conflicting options", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			return nil
+		}, dbx.WithNewTransaction(), dbx.WithReuseTransaction())
+
+		assert.ErrorIs(t, err, dbx.ErrConflictingOptions)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should rollback cancelled transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		cause := errors.New("aborted by supervisor")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		err := dbx.Transaction(context.Background(), db, func(c dbx.Context) error {
+			go c.Cancel(cause)
+
+			<-c.Done()
+
+			return context.Cause(c)
+		})
+
+		assert.Equal(t, cause, err)
+		assert.Eventually(t, func() bool {
+			return dmock.ExpectationsWereMet() == nil
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	test.Run("should rollback when deadline passes before commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := dbx.Transaction(ctx, db, func(c dbx.Context) error {
+			if _, e := c.Executor().Exec("SELECT 1"); e != nil {
+				return e
+			}
+
+			<-c.Done()
+
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Eventually(t, func() bool {
+			return dmock.ExpectationsWereMet() == nil
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestTransactionWithResult(test *testing.T) {
+	test.Run("should return operation result", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectCommit()
+
+		out, err := dbx.TransactionWithResult(context.Background(), db, func(c dbx.Context) (int, error) {
+			return 42, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, out)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should rollback when deadline passes before commit", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		out, err := dbx.TransactionWithResult(ctx, db, func(c dbx.Context) (int, error) {
+			<-c.Done()
+
+			return 42, nil
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, out)
+		assert.Eventually(t, func() bool {
+			return dmock.ExpectationsWereMet() == nil
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestThen(test *testing.T) {
+	test.Run("should pass first result to second operation within one transaction", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectQuery("INSERT INTO users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		dmock.ExpectExec("INSERT INTO profiles").WithArgs(7).WillReturnResult(sqlmock.NewResult(1, 1))
+		dmock.ExpectCommit()
+
+		var executor dbx.Executor
+
+		out, err := dbx.Then(context.Background(), db, func(c dbx.Context) (int64, error) {
+			var id int64
+			executor = c.Executor()
+
+			err := c.Executor().QueryRow("INSERT INTO users (name) VALUES ('John') RETURNING id").Scan(&id)
+
+			return id, err
+		}, func(c dbx.Context, id int64) (string, error) {
+			assert.Equal(t, executor, c.Executor())
+
+			_, err := c.Executor().Exec("INSERT INTO profiles (user_id) VALUES (?)", id)
+
+			return "created", err
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "created", out)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
+
+	test.Run("should not run second operation when first fails", func(t *testing.T) {
+		dbMock, dmock, _ := sqlmock.New()
+		defer dbMock.Close()
+
+		testErr := errors.New("test error")
+		db := dbx.New(dbMock)
+		dmock.ExpectBegin()
+		dmock.ExpectRollback()
+
+		_, err := dbx.Then(context.Background(), db, func(c dbx.Context) (int64, error) {
+			return 0, testErr
+		}, func(c dbx.Context, id int64) (string, error) {
+			assert.Fail(t, "second operation must not run")
+
+			return "", nil
+		})
+
+		assert.Equal(t, testErr, err)
+		assert.NoError(t, dmock.ExpectationsWereMet())
+	})
 }